@@ -1,124 +1,20 @@
-package main
+package day1
 
 import (
 	"bufio"
-	"errors"
-	"fmt"
-	"github.com/PuerkitoBio/goquery"
-	"io/ioutil"
 	"log"
-	"net/http"
-	"net/http/cookiejar"
-	"net/url"
-	"os"
-	"path"
-	"strconv"
-	"strings"
-)
-
-var URL = "https://adventofcode.com"
-
-func getAuth() *http.Cookie {
-	data, err := ioutil.ReadFile("../../auth.txt")
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	return &http.Cookie{
-		Name:     "session",
-		Value:    string(data),
-		Path:     "/",
-		Domain:   ".adventofcode.com",
-		Secure:   true,
-		HttpOnly: true,
-	}
-}
-
-func makeClient() *http.Client {
-	jar, err := cookiejar.New(nil)
-	if err != nil {
-		log.Fatalln(err)
-	}
-	urlObj, _ := url.Parse(URL)
-	jar.SetCookies(urlObj, []*http.Cookie{getAuth()})
 
-	return &http.Client{
-		Transport:     nil,
-		CheckRedirect: nil,
-		Jar:           jar,
-		Timeout:       0,
-	}
-}
-
-func getDocument(client *http.Client, url string) (*goquery.Document, error) {
-	// Get the HTML
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	} else if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, errors.New(resp.Status)
-	}
-
-	// Convert HTML into goquery document
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return doc, err
-	}
-	return doc, nil
-}
-
-func submit(client *http.Client, answer string) {
-	// Get the year and day from the path
-	dir, err := os.Getwd()
-	if err != nil {
-		log.Fatalln(err)
-	}
-	p, day := path.Split(dir)
-	_, year := path.Split(p)
-
-	// Submit the answers
-	resp, err := client.PostForm(fmt.Sprintf("%s/%s/day/%s/answer", URL, year, day), url.Values{
-		"level":  {day},
-		"answer": {answer},
-	})
-
-	defer resp.Body.Close()
-	if err != nil {
-		log.Fatalln(err)
-	}
+	"aoc/input"
+	"aoc/mathutil"
+	"aoc/registry"
+)
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+func getInput(scanner *bufio.Scanner) []int {
+	numbers, err := input.Ints(scanner)
 	if err != nil {
 		log.Fatalln(err)
 	}
-
-	// See if it's the right answer or not
-	if strings.Contains(doc.Find("main p").Text(), "not the right") {
-		log.Println("Wrong answer")
-	} else {
-		log.Println("Correct!")
-	}
-}
-
-func getInput(scanner *bufio.Scanner) (output []int) {
-	// Scan the input text
-	for scanner.Scan() {
-		// Fail on error
-		err := scanner.Err()
-		if err != nil {
-			log.Fatalln(err)
-		}
-
-		// Use the text in the output
-		line := scanner.Text()
-		// Convert to number
-		n, err := strconv.Atoi(line)
-		if err != nil {
-			log.Fatalln(err)
-		}
-		output = append(output, n)
-	}
-	return output
+	return numbers
 }
 
 func problem1(numbers []int) (larger int) {
@@ -130,40 +26,15 @@ func problem1(numbers []int) (larger int) {
 	return larger
 }
 
-func sum(nums ...int) (s int) {
-	for _, n := range nums {
-		s += n
-	}
-	return s
-}
-
 func problem2(numbers []int) (larger int) {
 	sums := make([]int, 0, len(numbers)-2)
 	// Create all of the sums
 	for i := 2; i < len(numbers); i++ {
-		sums = append(sums, sum(numbers[i-2:i+1]...))
+		sums = append(sums, mathutil.Sum(numbers[i-2:i+1]...))
 	}
 	return problem1(sums)
 }
 
-func main() {
-	// Read in the input for the day
-	file, err := os.Open("input.txt")
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	reader := bufio.NewReader(file)
-	scanner := bufio.NewScanner(reader)
-
-	// Get the output to submit to the server
-	numbers := getInput(scanner)
-	sol1 := problem1(numbers)
-	log.Println("Solution 1:", sol1)
-	sol2 := problem2(numbers)
-	log.Println("Solution 2:", sol2)
-
-	// Send the output to the server
-	// client := makeClient()
-	// submit(client, strconv.Itoa(output))
+func init() {
+	registry.Register(2021, 1, getInput, problem1, problem2)
 }
@@ -0,0 +1,47 @@
+package day3
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// checkExample runs solve on example.txt and compares it to the level's line of example_answers.txt
+func checkExample(t *testing.T, level int, solve func(scanner *bufio.Scanner) interface{}) {
+	data, err := os.ReadFile("example_answers.txt")
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("no example_answers.txt")
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if level > len(answers) || answers[level-1] == "" {
+		t.Skipf("no example answer for part %d yet", level)
+	}
+
+	file, err := os.Open("example.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if got := fmt.Sprint(solve(bufio.NewScanner(file))); got != answers[level-1] {
+		t.Errorf("part %d = %s, want %s", level, got, answers[level-1])
+	}
+}
+
+func TestProblem1(t *testing.T) {
+	checkExample(t, 1, func(scanner *bufio.Scanner) interface{} {
+		return problem1(getInput(scanner))
+	})
+}
+
+func TestProblem2(t *testing.T) {
+	checkExample(t, 2, func(scanner *bufio.Scanner) interface{} {
+		return problem2(getInput(scanner))
+	})
+}
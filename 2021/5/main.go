@@ -1,120 +1,14 @@
-package main
+package day5
 
 import (
 	"bufio"
-	"errors"
-	"fmt"
-	"github.com/PuerkitoBio/goquery"
-	"io/ioutil"
 	"log"
-	"net/http"
-	"net/http/cookiejar"
-	"net/url"
-	"os"
-	"path"
 	"strconv"
 	"strings"
-)
-
-var URL = "https://adventofcode.com"
-
-func getAuth() *http.Cookie {
-	data, err := ioutil.ReadFile("../../auth.txt")
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	return &http.Cookie{
-		Name:     "session",
-		Value:    string(data),
-		Path:     "/",
-		Domain:   ".adventofcode.com",
-		Secure:   true,
-		HttpOnly: true,
-	}
-}
-
-func makeClient() *http.Client {
-	jar, err := cookiejar.New(nil)
-	if err != nil {
-		log.Fatalln(err)
-	}
-	urlObj, _ := url.Parse(URL)
-	jar.SetCookies(urlObj, []*http.Cookie{getAuth()})
-
-	return &http.Client{
-		Transport:     nil,
-		CheckRedirect: nil,
-		Jar:           jar,
-		Timeout:       0,
-	}
-}
-
-func getDocument(client *http.Client, url string) (*goquery.Document, error) {
-	// Get the HTML
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	} else if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, errors.New(resp.Status)
-	}
-
-	// Convert HTML into goquery document
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return doc, err
-	}
-	return doc, nil
-}
-
-func submit(client *http.Client, answer string) {
-	// Get the year and day from the path
-	dir, err := os.Getwd()
-	if err != nil {
-		log.Fatalln(err)
-	}
-	p, day := path.Split(dir)
-	_, year := path.Split(p)
-
-	// Submit the answers
-	resp, err := client.PostForm(fmt.Sprintf("%s/%s/day/%s/answer", URL, year, day), url.Values{
-		"level":  {day},
-		"answer": {answer},
-	})
-
-	defer resp.Body.Close()
-	if err != nil {
-		log.Fatalln(err)
-	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	// See if it's the right answer or not
-	if strings.Contains(doc.Find("main p").Text(), "not the right") {
-		log.Println("Wrong answer")
-	} else {
-		log.Println("Correct!")
-	}
-}
-
-func max(a, b int) int {
-	if a >= b {
-		return a
-	} else {
-		return b
-	}
-}
-
-func abs(a int) int {
-	if a > -1 {
-		return a
-	} else {
-		return -a
-	}
-}
+	"aoc/mathutil"
+	"aoc/registry"
+)
 
 type Point struct {
 	X int
@@ -145,7 +39,7 @@ func (grid *Grid) Add(l Line, diagonal bool) {
 		ySign = -1
 	}
 
-	for i := 0; i <= max(abs(x), abs(y)); i++ {
+	for i := 0; i <= mathutil.Max(mathutil.Abs(x), mathutil.Abs(y)); i++ {
 		var point Point
 		if x != 0 && y != 0 {
 			// Assume the diagonals have a slope of 1
@@ -228,25 +122,6 @@ func problem2(input []Line) (output int) {
 	return output
 }
 
-func main() {
-	// Read in the input for the day
-	file, err := os.Open("input.txt")
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	reader := bufio.NewReader(file)
-	scanner := bufio.NewScanner(reader)
-
-	// Get the output to submit to the server
-	input := getInput(scanner)
-	sol1 := problem1(input)
-	log.Println("Solution 1:", sol1)
-	sol2 := problem2(input)
-	log.Println("Solution 2:", sol2)
-
-	// Send the output to the server
-	// client := makeClient()
-	// submit(client, sol1)
-	// submit(client, sol2)
+func init() {
+	registry.Register(2021, 5, getInput, problem1, problem2)
 }
@@ -0,0 +1,167 @@
+package day8
+
+import (
+	"bufio"
+	"log"
+	"sort"
+	"strings"
+
+	"aoc/constraint"
+	"aoc/input"
+	"aoc/registry"
+)
+
+// digitSegments holds the lit segments of each digit, with a to g as 0 to 6
+var digitSegments = [10]constraint.Set{
+	segments("abcefg"),
+	segments("cf"),
+	segments("acdeg"),
+	segments("acdfg"),
+	segments("bcdf"),
+	segments("abdfg"),
+	segments("abdefg"),
+	segments("acf"),
+	segments("abcdefg"),
+	segments("abcdfg"),
+}
+
+// Display is one line of the notes: the ten unique patterns and the four digits of the output
+type Display struct {
+	Patterns []constraint.Set
+	Output   []constraint.Set
+}
+
+func segments(s string) (set constraint.Set) {
+	for _, c := range s {
+		set |= constraint.SetOf(int(c - 'a'))
+	}
+	return set
+}
+
+func getInput(scanner *bufio.Scanner) (output []Display) {
+	lines, err := input.Lines(scanner)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	for _, line := range lines {
+		parts := strings.Split(line, "|")
+		if len(parts) != 2 {
+			log.Fatalf("%q has no output\n", line)
+		}
+		display := Display{}
+		for _, pattern := range strings.Fields(parts[0]) {
+			display.Patterns = append(display.Patterns, segments(pattern))
+		}
+		for _, digit := range strings.Fields(parts[1]) {
+			display.Output = append(display.Output, segments(digit))
+		}
+		output = append(output, display)
+	}
+	return output
+}
+
+// signature lists the sizes of the sets containing v, which tells the segments apart
+func signature(sets []constraint.Set, v int) string {
+	var sizes []int
+	for _, set := range sets {
+		if set.Has(v) {
+			sizes = append(sizes, set.Len())
+		}
+	}
+	sort.Ints(sizes)
+
+	b := &strings.Builder{}
+	for _, size := range sizes {
+		b.WriteByte(byte('0' + size))
+	}
+	return b.String()
+}
+
+// rewire maps the wires of a pattern onto the segments they light
+func rewire(pattern constraint.Set, mapping []int) (lit constraint.Set) {
+	for _, wire := range pattern.Values() {
+		lit |= constraint.SetOf(mapping[wire])
+	}
+	return lit
+}
+
+// digit returns the digit lit by the segments, or -1 if there isn't one
+func digit(lit constraint.Set) int {
+	for d, segments := range digitSegments {
+		if segments == lit {
+			return d
+		}
+	}
+	return -1
+}
+
+// solve works out which segment each wire lights
+func solve(display Display) []int {
+	wires := constraint.NewPermutation(7)
+	for wire := 0; wire < 7; wire++ {
+		// A wire lights a segment in as many digits of each size as the segment is in
+		var allowed constraint.Set
+		for segment := 0; segment < 7; segment++ {
+			if signature(display.Patterns, wire) == signature(digitSegments[:], segment) {
+				allowed |= constraint.SetOf(segment)
+			}
+		}
+		wires.Restrict(wire, allowed)
+	}
+
+	// The wires of a pattern light the segments of a digit with as many segments
+	for _, pattern := range display.Patterns {
+		var allowed constraint.Set
+		for _, segments := range digitSegments {
+			if segments.Len() == pattern.Len() {
+				allowed |= segments
+			}
+		}
+		for _, wire := range pattern.Values() {
+			wires.Restrict(wire, allowed)
+		}
+	}
+
+	mapping, ok := wires.Solve(func(mapping []int) bool {
+		for _, pattern := range display.Patterns {
+			if digit(rewire(pattern, mapping)) == -1 {
+				return false
+			}
+		}
+		return true
+	})
+	if !ok {
+		log.Fatalln("no wiring lights every pattern as a digit")
+	}
+	return mapping
+}
+
+func problem1(input []Display) (output int) {
+	// 1, 4, 7 and 8 are the only digits with their number of segments
+	for _, display := range input {
+		for _, digit := range display.Output {
+			switch digit.Len() {
+			case 2, 3, 4, 7:
+				output++
+			}
+		}
+	}
+	return output
+}
+
+func problem2(input []Display) (output int) {
+	for _, display := range input {
+		mapping := solve(display)
+		value := 0
+		for _, pattern := range display.Output {
+			value = value*10 + digit(rewire(pattern, mapping))
+		}
+		output += value
+	}
+	return output
+}
+
+func init() {
+	registry.Register(2021, 8, getInput, problem1, problem2)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// analysis is what the shape of an input looks like
+type analysis struct {
+	// parser names the entry of parsers that reads this shape
+	parser string
+	// summary describes the input in a sentence for the header of the solution
+	summary string
+}
+
+var (
+	intRegex       = regexp.MustCompile(`^\s*-?\d+\s*$`)
+	commaIntsRegex = regexp.MustCompile(`^-?\d+(,-?\d+)+$`)
+	digitsRegex    = regexp.MustCompile(`^\d+$`)
+	bitsRegex      = regexp.MustCompile(`^[01]+$`)
+	commandRegex   = regexp.MustCompile(`^([a-z]+) (-?\d+)$`)
+	segmentRegex   = regexp.MustCompile(`^(\d+),(\d+) -> (\d+),(\d+)$`)
+	numberRegex    = regexp.MustCompile(`-?\d+`)
+)
+
+// analyzeInput works out the shape of an input, trying the most specific shapes first
+func analyzeInput(data []byte) analysis {
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return analysis{parser: "lines", summary: "The input is empty."}
+	}
+	lines := strings.Split(text, "\n")
+	n := len(lines)
+
+	switch {
+	case strings.Contains(text, "\n\n"):
+		blocks := strings.Split(text, "\n\n")
+		return analysis{"blocks", fmt.Sprintf("The input is %d %s separated by blank lines, the first with %d %s and the rest with %s.",
+			len(blocks), plural(len(blocks), "block"),
+			strings.Count(blocks[0], "\n")+1, plural(strings.Count(blocks[0], "\n")+1, "line"),
+			lineCounts(blocks[1:]))}
+	case all(lines, segmentRegex):
+		return analysis{"segments", fmt.Sprintf(`The input is %d "x,y -> x,y" %s with coordinates %s.`,
+			n, plural(n, "segment"), numberRange(text))}
+	case all(lines, commandRegex):
+		words := map[string]bool{}
+		for _, line := range lines {
+			words[commandRegex.FindStringSubmatch(line)[1]] = true
+		}
+		return analysis{"commands", fmt.Sprintf(`The input is %d "word int" %s using %s with numbers %s.`,
+			n, plural(n, "command"), joinWords(words), numberRange(text))}
+	case n > 1 && all(lines, bitsRegex) && sameLength(lines):
+		return analysis{"bits", fmt.Sprintf("The input is %d %d-bit binary %s.", n, len(lines[0]), plural(n, "number"))}
+	case isDigitGrid(lines):
+		return analysis{"digits", fmt.Sprintf("The input is a %dx%d grid of digits.", len(lines[0]), n)}
+	case all(lines, intRegex):
+		return analysis{"ints", fmt.Sprintf("The input is %d %s, one per line, %s.", n, plural(n, "number"), numberRange(text))}
+	case n == 1 && commaIntsRegex.MatchString(lines[0]):
+		count := strings.Count(lines[0], ",") + 1
+		return analysis{"commaints", fmt.Sprintf("The input is %d comma separated %s %s.", count, plural(count, "number"), numberRange(text))}
+	}
+
+	longest := 0
+	for _, line := range lines {
+		if len(line) > longest {
+			longest = len(line)
+		}
+	}
+	return analysis{"lines", fmt.Sprintf("The input is %d %s of text up to %d characters long.", n, plural(n, "line"), longest)}
+}
+
+// isDigitGrid tells a grid of digits from a list of numbers by its long rows or leading zeros
+func isDigitGrid(lines []string) bool {
+	if len(lines) < 2 || !all(lines, digitsRegex) || !sameLength(lines) {
+		return false
+	}
+	if len(lines[0]) >= 10 {
+		return true
+	}
+	for _, line := range lines {
+		if line[0] == '0' {
+			return true
+		}
+	}
+	return false
+}
+
+func all(lines []string, re *regexp.Regexp) bool {
+	for _, line := range lines {
+		if !re.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameLength(lines []string) bool {
+	for _, line := range lines {
+		if len(line) != len(lines[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// numberRange describes the smallest and largest numbers in the text
+func numberRange(text string) string {
+	min, max := 0, 0
+	for i, match := range numberRegex.FindAllString(text, -1) {
+		n, _ := strconv.Atoi(match)
+		if i == 0 || n < min {
+			min = n
+		}
+		if i == 0 || n > max {
+			max = n
+		}
+	}
+	return fmt.Sprintf("from %d to %d", min, max)
+}
+
+// lineCounts describes how many lines the blocks have
+func lineCounts(blocks []string) string {
+	if len(blocks) == 0 {
+		return "nothing else"
+	}
+	counts := map[int]bool{}
+	for _, block := range blocks {
+		counts[strings.Count(block, "\n")+1] = true
+	}
+	if len(counts) == 1 {
+		for count := range counts {
+			return fmt.Sprintf("%d %s each", count, plural(count, "line"))
+		}
+	}
+	return "different numbers of lines"
+}
+
+// joinWords lists the words in order, such as "down, forward and up"
+func joinWords(words map[string]bool) string {
+	list := make([]string, 0, len(words))
+	for word := range words {
+		list = append(list, word)
+	}
+	sort.Strings(list)
+	if len(list) == 1 {
+		return list[0]
+	}
+	return strings.Join(list[:len(list)-1], ", ") + " and " + list[len(list)-1]
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestAnalyzeExamples(t *testing.T) {
+	// The shapes of the 2021 examples, which are the same as their inputs
+	for day, want := range map[string]string{
+		"1":  "ints",
+		"2":  "commands",
+		"3":  "bits",
+		"4":  "blocks",
+		"5":  "segments",
+		"6":  "commaints",
+		"7":  "commaints",
+		"8":  "lines",
+		"9":  "digits",
+		"10": "lines",
+	} {
+		data, err := os.ReadFile("2021/" + day + "/example.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := analyzeInput(data); got.parser != want || got.summary == "" {
+			t.Errorf("day %s = %q (%s), want %q", day, got.parser, got.summary, want)
+		}
+	}
+}
+
+func TestAnalyzeInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		parser  string
+		summary string
+	}{
+		{"empty", "", "lines", "The input is empty."},
+		{"ints", "199\n-200\n208\n", "ints", "The input is 3 numbers, one per line, from -200 to 208."},
+		{"one int", "42\n", "ints", "The input is 1 number, one per line, from 42 to 42."},
+		{"comma ints", "3,4,3,1,2\n", "commaints", "The input is 5 comma separated numbers from 1 to 4."},
+		{"commands", "forward 5\ndown 5\nup 3\n", "commands", `The input is 3 "word int" commands using down, forward and up with numbers from 3 to 5.`},
+		{"bits", "00100\n11110\n", "bits", "The input is 2 5-bit binary numbers."},
+		{"segments", "0,9 -> 5,9\n8,0 -> 0,8\n", "segments", `The input is 2 "x,y -> x,y" segments with coordinates from 0 to 9.`},
+		{"blocks", "1,2\n\na\nb\n\nc\nd\n", "blocks", "The input is 3 blocks separated by blank lines, the first with 1 line and the rest with 2 lines each."},
+		{"blocks of different sizes", "a\n\nb\n\nc\nd\n", "blocks", "The input is 3 blocks separated by blank lines, the first with 1 line and the rest with different numbers of lines."},
+		// Short rows of digits without leading zeros are more likely numbers
+		{"digit grid", "0123\n4567\n", "digits", "The input is a 4x2 grid of digits."},
+		{"numbers of the same length", "1234\n5678\n", "ints", "The input is 2 numbers, one per line, from 1234 to 5678."},
+		{"text", "abc\nde\n", "lines", "The input is 2 lines of text up to 3 characters long."},
+		{"bits of different lengths", "0101\n11\n", "ints", "The input is 2 numbers, one per line, from 11 to 101."},
+	}
+
+	for _, test := range tests {
+		got := analyzeInput([]byte(test.input))
+		if got.parser != test.parser || got.summary != test.summary {
+			t.Errorf("%s = %q, %q, want %q, %q", test.name, got.parser, got.summary, test.parser, test.summary)
+		}
+	}
+}
+
+func TestAnalyzeParsersExist(t *testing.T) {
+	for _, input := range []string{"", "1\n", "1,2\n", "up 1\n", "01\n10\n", "0,0 -> 1,1\n", "a\n\nb\n", "0123\n4567\n", "text\n"} {
+		if name := analyzeInput([]byte(input)).parser; parsers[name].Func == "" {
+			t.Errorf("%q picks %q, which isn't a parser", input, name)
+		}
+	}
+}
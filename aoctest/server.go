@@ -0,0 +1,321 @@
+// Package aoctest runs a fake Advent of Code site for testing the fetcher and submitter offline.
+//
+// The server serves a calendar for each year, puzzle pages, inputs and answer
+// responses much like the real site, using the same markup the scrapers rely on.
+// Point a client at it with client.NewWithURL(server.URL) or by setting AOC_URL.
+package aoctest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Puzzle is a day served by the fake site
+type Puzzle struct {
+	Title string
+	// Parts holds the HTML inside the article of each part of the description
+	Parts [2]string
+	Input string
+	// Answers holds the right answer for each part
+	Answers [2]string
+}
+
+type key struct {
+	year, day int
+}
+
+// Server is a fake Advent of Code site
+type Server struct {
+	*httptest.Server
+
+	// Session is the cookie every request must have, any session is accepted if empty
+	Session string
+	// Penalty is how long to refuse answers after a wrong one
+	Penalty time.Duration
+
+	mu          sync.Mutex
+	puzzles     map[key]*Puzzle
+	solved      map[key]int
+	cooldown    time.Time
+	requests    map[string]int
+	notModified map[string]int
+}
+
+var (
+	calendarPath = regexp.MustCompile(`^/(\d+)/?$`)
+	dayPath      = regexp.MustCompile(`^/(\d+)/day/(\d+)$`)
+	inputPath    = regexp.MustCompile(`^/(\d+)/day/(\d+)/input$`)
+	answerPath   = regexp.MustCompile(`^/(\d+)/day/(\d+)/answer$`)
+	boardPath    = regexp.MustCompile(`^/(\d+)/leaderboard/private/view/(\d+)\.json$`)
+	settingsPath = "/settings"
+)
+
+// NewServer starts a fake site with no puzzles, which should be closed when done
+func NewServer() *Server {
+	s := &Server{
+		Penalty:     time.Minute,
+		puzzles:     make(map[key]*Puzzle),
+		solved:      make(map[key]int),
+		requests:    make(map[string]int),
+		notModified: make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddPuzzle makes a day available on the site
+func (s *Server) AddPuzzle(year, day int, p Puzzle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.puzzles[key{year, day}] = &p
+}
+
+// Solved returns how many parts of a day have been answered correctly
+func (s *Server) Solved(year, day int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.solved[key{year, day}]
+}
+
+// SetSolved marks the first parts of a day as already answered
+func (s *Server) SetSolved(year, day, parts int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.solved[key{year, day}] = parts
+}
+
+// Requests returns how many times a path was requested
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// NotModified returns how many times a path was answered with 304 Not Modified
+func (s *Server) NotModified(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notModified[path]
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[r.URL.Path]++
+
+	if r.Method != http.MethodGet {
+		s.route(w, r)
+		return
+	}
+
+	// Pages carry an ETag of their contents, so an unchanged page can be revalidated
+	rec := httptest.NewRecorder()
+	s.route(rec, r)
+	if rec.Code == http.StatusOK {
+		sum := sha256.Sum256(rec.Body.Bytes())
+		etag := fmt.Sprintf(`"%x"`, sum[:8])
+		rec.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			s.notModified[r.URL.Path]++
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	for name, values := range rec.Header() {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(rec.Code)
+	rec.Body.WriteTo(w)
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	// The site only serves personal pages to logged in users
+	if s.Session != "" {
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != s.Session {
+			if inputPath.MatchString(r.URL.Path) {
+				http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			} else {
+				http.Error(w, "Please log in.", http.StatusBadRequest)
+			}
+			return
+		}
+	}
+
+	path := r.URL.Path
+	switch {
+	case path == settingsPath:
+		fmt.Fprint(w, `<html><body><header><div class="user">test</div></header><main><form method="post" action="/settings"></form></main></body></html>`)
+	case calendarPath.MatchString(path):
+		year := atoi(calendarPath.FindStringSubmatch(path)[1])
+		s.calendar(w, year)
+	case dayPath.MatchString(path):
+		m := dayPath.FindStringSubmatch(path)
+		s.day(w, key{atoi(m[1]), atoi(m[2])})
+	case inputPath.MatchString(path):
+		m := inputPath.FindStringSubmatch(path)
+		p, ok := s.puzzles[key{atoi(m[1]), atoi(m[2])}]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, p.Input)
+	case answerPath.MatchString(path) && r.Method == http.MethodPost:
+		m := answerPath.FindStringSubmatch(path)
+		s.answer(w, r, key{atoi(m[1]), atoi(m[2])})
+	case boardPath.MatchString(path):
+		s.leaderboard(w, atoi(boardPath.FindStringSubmatch(path)[1]))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) calendar(w http.ResponseWriter, year int) {
+	var days []int
+	for k := range s.puzzles {
+		if k.year == year {
+			days = append(days, k.day)
+		}
+	}
+	if len(days) == 0 {
+		http.Error(w, "404 Not Found", http.StatusNotFound)
+		return
+	}
+	sort.Ints(days)
+
+	fmt.Fprint(w, `<html><body><main><pre class="calendar">`)
+	for _, day := range days {
+		class := fmt.Sprintf("calendar-day%d", day)
+		switch s.solved[key{year, day}] {
+		case 1:
+			class += " calendar-complete"
+		case 2:
+			class += " calendar-verycomplete"
+		}
+		fmt.Fprintf(w, `<a aria-label="Day %d" href="/%d/day/%d" class="%s">  <span class="calendar-day">%2d</span></a>`+"\n",
+			day, year, day, class, day)
+	}
+	fmt.Fprint(w, `</pre></main></body></html>`)
+}
+
+func (s *Server) day(w http.ResponseWriter, k key) {
+	p, ok := s.puzzles[k]
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	solved := s.solved[k]
+
+	fmt.Fprint(w, `<html><body><main>`)
+	fmt.Fprintf(w, `<article class="day-desc"><h2>--- Day %d: %s ---</h2>%s</article>`, k.day, html.EscapeString(p.Title), p.Parts[0])
+	if solved >= 1 {
+		fmt.Fprintf(w, `<p>Your puzzle answer was <code>%s</code>.</p>`, html.EscapeString(p.Answers[0]))
+		fmt.Fprintf(w, `<article class="day-desc"><h2 id="part2">--- Part Two ---</h2>%s</article>`, p.Parts[1])
+	}
+	if solved >= 2 {
+		fmt.Fprintf(w, `<p>Your puzzle answer was <code>%s</code>.</p>`, html.EscapeString(p.Answers[1]))
+	}
+	fmt.Fprint(w, `</main></body></html>`)
+}
+
+func (s *Server) answer(w http.ResponseWriter, r *http.Request, k key) {
+	p, ok := s.puzzles[k]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	level := atoi(r.FormValue("level"))
+	answer := r.FormValue("answer")
+	if level != 1 && level != 2 {
+		http.Error(w, "400 Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	message := ""
+	switch {
+	case time.Now().Before(s.cooldown):
+		left := time.Until(s.cooldown).Round(time.Second)
+		message = fmt.Sprintf("You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have %s left to wait.", formatWait(left))
+	case level != s.solved[k]+1:
+		message = "You don't seem to be solving the right level.  Did you already complete it?"
+	case answer == p.Answers[level-1]:
+		s.solved[k] = level
+		message = "That's the right answer!  You are one gold star closer to saving your vacation."
+	default:
+		message = "That's not the right answer"
+		if hint := compare(answer, p.Answers[level-1]); hint != "" {
+			message += "; your answer is " + hint
+		}
+		message += "."
+		if s.Penalty > 0 {
+			message += fmt.Sprintf("  Please wait %s before trying again.", formatPenalty(s.Penalty))
+			s.cooldown = time.Now().Add(s.Penalty)
+		}
+	}
+	fmt.Fprintf(w, `<html><body><main><article><p>%s</p></article></main></body></html>`, html.EscapeString(message))
+}
+
+func (s *Server) leaderboard(w http.ResponseWriter, year int) {
+	stars := 0
+	for k, parts := range s.solved {
+		if k.year == year {
+			stars += parts
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"event":    strconv.Itoa(year),
+		"owner_id": 1,
+		"members": map[string]interface{}{
+			"1": map[string]interface{}{"id": 1, "name": "test", "stars": stars, "local_score": stars * 10},
+		},
+	})
+}
+
+// compare gives the hint the site gives for numeric answers
+func compare(answer, right string) string {
+	a, ok1 := new(big.Int).SetString(answer, 10)
+	b, ok2 := new(big.Int).SetString(right, 10)
+	if !ok1 || !ok2 {
+		return ""
+	}
+	switch a.Cmp(b) {
+	case 1:
+		return "too high"
+	case -1:
+		return "too low"
+	}
+	return ""
+}
+
+func formatWait(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	seconds := int((d % time.Minute) / time.Second)
+	if minutes > 0 {
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+func formatPenalty(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes <= 1 {
+		return "one minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
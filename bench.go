@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"aoc/registry"
+)
+
+// measurement is the average cost of one step of a solution
+type measurement struct {
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp uint64 `json:"allocs_per_op"`
+	BytesPerOp  uint64 `json:"bytes_per_op"`
+}
+
+// benchResult is how long each step of a day took
+type benchResult struct {
+	Year       int         `json:"year"`
+	Day        int         `json:"day"`
+	Iterations int         `json:"iterations"`
+	Parse      measurement `json:"parse"`
+	Part1      measurement `json:"part1"`
+	Part2      measurement `json:"part2"`
+}
+
+// benchReport is written as JSON so runs can be compared between commits
+type benchReport struct {
+	Commit    string        `json:"commit,omitempty"`
+	GoVersion string        `json:"go_version"`
+	Time      time.Time     `json:"time"`
+	Input     string        `json:"input"`
+	Results   []benchResult `json:"results"`
+}
+
+// meter adds up the time and allocations of the calls it measures
+type meter struct {
+	elapsed time.Duration
+	allocs  uint64
+	bytes   uint64
+}
+
+func (m *meter) measure(f func()) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	f()
+	m.elapsed += time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	m.allocs += after.Mallocs - before.Mallocs
+	m.bytes += after.TotalAlloc - before.TotalAlloc
+}
+
+func (m *meter) perOp(n int) measurement {
+	return measurement{
+		NsPerOp:     m.elapsed.Nanoseconds() / int64(n),
+		AllocsPerOp: m.allocs / uint64(n),
+		BytesPerOp:  m.bytes / uint64(n),
+	}
+}
+
+// benchmark runs the parser and both parts of a day n times, parsing again before each part
+func benchmark(s *registry.Solution, data []byte, n int) benchResult {
+	var parse, part1, part2 meter
+	var input interface{}
+	for i := 0; i < n; i++ {
+		parse.measure(func() {
+			input = s.Read(bytes.NewReader(data))
+		})
+		part1.measure(func() {
+			s.Parts[0](input)
+		})
+
+		// Part one may have changed the input
+		input = s.Read(bytes.NewReader(data))
+		part2.measure(func() {
+			s.Parts[1](input)
+		})
+	}
+
+	return benchResult{
+		Year:       s.Year,
+		Day:        s.Day,
+		Iterations: n,
+		Parse:      parse.perOp(n),
+		Part1:      part1.perOp(n),
+		Part2:      part2.perOp(n),
+	}
+}
+
+func printBenchTable(w io.Writer, results []benchResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "day\tparse\tallocs\tpart 1\tallocs\tpart 2\tallocs\ttotal\t")
+
+	var total time.Duration
+	for _, r := range results {
+		dayTotal := time.Duration(r.Parse.NsPerOp + r.Part1.NsPerOp + r.Part2.NsPerOp)
+		total += dayTotal
+		fmt.Fprintf(tw, "%d/%d\t%v\t%d\t%v\t%d\t%v\t%d\t%v\t\n", r.Year, r.Day,
+			time.Duration(r.Parse.NsPerOp), r.Parse.AllocsPerOp,
+			time.Duration(r.Part1.NsPerOp), r.Part1.AllocsPerOp,
+			time.Duration(r.Part2.NsPerOp), r.Part2.AllocsPerOp,
+			dayTotal)
+	}
+	fmt.Fprintf(tw, "total\t\t\t\t\t\t\t%v\t\n", total)
+	tw.Flush()
+}
+
+func writeBenchReport(name string, report benchReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, append(data, '\n'), 0644)
+}
+
+// currentCommit returns the commit being benchmarked, if there is one
+func currentCommit() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
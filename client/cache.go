@@ -0,0 +1,218 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// forever is the expiry of cached pages that never change, such as inputs
+var forever = time.Time{}
+
+const (
+	// sessionCheckTTL is how long a successful session check is trusted
+	sessionCheckTTL = time.Hour
+	// dayPageTTL is how long a puzzle page is trusted before it is revalidated,
+	// since it changes when a part is solved, even from a browser
+	dayPageTTL = time.Hour
+)
+
+var (
+	calendarPath = regexp.MustCompile(`^/(\d+)/?$`)
+	dayPath      = regexp.MustCompile(`^/(\d+)/day/(\d+)$`)
+	inputPath    = regexp.MustCompile(`^/(\d+)/day/(\d+)/input$`)
+)
+
+// cacheEntry is a page saved to disk along with what is needed to revalidate it
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Expires      time.Time `json:"expires"`
+	Body         []byte    `json:"body"`
+}
+
+func (e *cacheEntry) fresh(now time.Time) bool {
+	return e.Expires.IsZero() || now.Before(e.Expires)
+}
+
+// response turns the entry back into the response it was saved from
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// DefaultCacheDir is where pages are cached, under the user's cache directory ($XDG_CACHE_HOME on Linux)
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aoc", "http")
+}
+
+// cacheExpiry returns how long a page stays fresh, or false if it shouldn't be cached at all.
+// Inputs never change, a puzzle page changes when a part is solved,
+// and the calendar changes when the next day unlocks.
+func cacheExpiry(path string, now time.Time) (time.Time, bool) {
+	switch {
+	case inputPath.MatchString(path):
+		return forever, true
+	case dayPath.MatchString(path):
+		return now.Add(dayPageTTL), true
+	case calendarPath.MatchString(path):
+		year, _ := strconv.Atoi(calendarPath.FindStringSubmatch(path)[1])
+		if day := NextUnlock(year, now); day != 0 {
+			return UnlockTime(year, day), true
+		}
+		return forever, true
+	case path == "/settings":
+		return now.Add(sessionCheckTTL), true
+	}
+	return time.Time{}, false
+}
+
+// cacheName is the file a page is cached in, which depends on the session since pages differ by user
+func (c *Client) cacheName(url string) string {
+	sum := sha256.Sum256([]byte(c.session + "\n" + url))
+	return filepath.Join(c.CacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *Client) loadCache(url string) (*cacheEntry, error) {
+	data, err := os.ReadFile(c.cacheName(url))
+	if err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (c *Client) saveCache(entry *cacheEntry) error {
+	if err := os.MkdirAll(c.CacheDir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write the entry next to where it goes and rename it, so concurrent readers never see half of it
+	name := c.cacheName(entry.URL)
+	tmp, err := os.CreateTemp(c.CacheDir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Forget removes a page from the cache, so it is downloaded again the next time it's needed
+func (c *Client) Forget(url string) error {
+	if c.CacheDir == "" {
+		return nil
+	}
+	err := os.Remove(c.cacheName(url))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// cached answers GET requests from the cache while they are fresh, revalidates them
+// once they're stale, and falls back on a stale page when the site can't be reached
+func (c *Client) cached(req *http.Request, fetch func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	now := time.Now()
+	expires, ok := cacheExpiry(req.URL.Path, now)
+	if !ok || req.Method != http.MethodGet || c.CacheDir == "" {
+		return fetch(req)
+	}
+
+	url := req.URL.String()
+	entry, err := c.loadCache(url)
+	if err != nil {
+		entry = nil
+	}
+	if entry != nil && entry.fresh(now) && !c.Refresh {
+		return entry.response(req), nil
+	}
+
+	// Ask the site if the saved page has changed
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := fetch(req)
+	if err != nil {
+		// Keep working offline with what was saved, but never hide an expired session
+		if entry != nil && !errors.Is(err, ErrLoggedOut) {
+			return entry.response(req), nil
+		}
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		resp.Body.Close()
+		entry.Expires = expires
+		c.saveCache(entry)
+		return entry.response(req), nil
+	case resp.StatusCode != http.StatusOK:
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// A failure to cache shouldn't fail the request
+	c.saveCache(&cacheEntry{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Expires:      expires,
+		Body:         body,
+	})
+	return resp, nil
+}
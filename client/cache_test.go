@@ -0,0 +1,80 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"aoc/aoctest"
+)
+
+func TestCacheRevalidates(t *testing.T) {
+	RequestInterval = 0
+	s := aoctest.NewServer()
+	t.Cleanup(s.Close)
+	s.AddPuzzle(2030, 1, aoctest.Puzzle{Title: "Test", Parts: [2]string{"<p>Part one</p>", ""}})
+
+	c, err := NewWithSession(s.URL, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CacheDir = t.TempDir()
+
+	page := s.URL + "/2030/day/1"
+	get := func() string {
+		t.Helper()
+		resp, err := c.Get(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got %s", resp.Status)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	want := get()
+	entry, err := c.loadCache(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.ETag == "" {
+		t.Fatal("the page was cached without its ETag")
+	}
+
+	// A fresh page doesn't go back to the site
+	if got := get(); got != want || s.Requests("/2030/day/1") != 1 {
+		t.Errorf("fresh page = %q after %d requests", got, s.Requests("/2030/day/1"))
+	}
+
+	// A stale one is revalidated and served from the cache while unchanged
+	entry.Expires = time.Now().Add(-time.Minute)
+	if err := c.saveCache(entry); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(); got != want {
+		t.Errorf("revalidated page = %q, want %q", got, want)
+	}
+	if s.NotModified("/2030/day/1") != 1 {
+		t.Errorf("%d pages not modified, want the stale one", s.NotModified("/2030/day/1"))
+	}
+	if entry, err := c.loadCache(page); err != nil || !entry.fresh(time.Now()) {
+		t.Errorf("the revalidated entry is still stale: %v", err)
+	}
+
+	// A changed page replaces the stale one
+	s.SetSolved(2030, 1, 1)
+	c.Refresh = true
+	if got := get(); got == want {
+		t.Error("the changed page came from the cache")
+	}
+	if s.NotModified("/2030/day/1") != 1 {
+		t.Error("a changed page was not modified")
+	}
+}
@@ -0,0 +1,114 @@
+// Package client talks to the Advent of Code website with a session cookie.
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DefaultURL is the site new clients talk to unless URLEnv is set
+const DefaultURL = "https://adventofcode.com"
+
+// URLEnv is the environment variable that changes the site, such as to a local test server
+const URLEnv = "AOC_URL"
+
+// Client is an HTTP client logged in to the site
+type Client struct {
+	*http.Client
+	// URL is the base URL of the site without a trailing slash
+	URL string
+	// UserAgent is sent with every request, see UserAgentEnv
+	UserAgent string
+	// CacheDir holds the pages saved from the site, or is empty to not cache anything
+	CacheDir string
+	// Refresh checks cached pages with the site even when they're still fresh
+	Refresh bool
+
+	session string
+}
+
+// BaseURL returns the site to talk to, which is DefaultURL unless URLEnv is set
+func BaseURL() string {
+	if base := os.Getenv(URLEnv); base != "" {
+		return strings.TrimSuffix(base, "/")
+	}
+	return DefaultURL
+}
+
+// New returns a client for BaseURL logged in with the session from Session
+func New() (*Client, error) {
+	return NewWithURL(BaseURL())
+}
+
+// NewWithURL returns a client for the site at base logged in with the session from Session
+func NewWithURL(base string) (*Client, error) {
+	session, err := Session()
+	if err != nil {
+		return nil, err
+	}
+	return NewWithSession(base, session)
+}
+
+// NewWithSession returns a client for the site at base logged in with session
+func NewWithSession(base, session string) (*Client, error) {
+	auth := &http.Cookie{
+		Name:     "session",
+		Value:    strings.TrimSpace(session),
+		Path:     "/",
+		Domain:   ".adventofcode.com",
+		Secure:   true,
+		HttpOnly: true,
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	base = strings.TrimSuffix(base, "/")
+	urlObj, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scope the cookie to the site actually used, which may be a plain HTTP test server
+	if urlObj.Hostname() != "adventofcode.com" {
+		auth.Domain = ""
+		auth.Secure = urlObj.Scheme == "https"
+	}
+	jar.SetCookies(urlObj, []*http.Cookie{auth})
+
+	c := &Client{
+		URL:       base,
+		UserAgent: UserAgent(),
+		CacheDir:  DefaultCacheDir(),
+		session:   auth.Value,
+	}
+	c.Client = &http.Client{
+		Jar:       jar,
+		Transport: newTransport(c),
+	}
+	return c, nil
+}
+
+// Document gets the page at url as a goquery document
+func (c *Client) Document(url string) (*goquery.Document, error) {
+	// Get the HTML
+	resp, err := c.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 || resp.StatusCode < 200 {
+		return nil, errors.New(resp.Status)
+	}
+
+	// Convert HTML into goquery document
+	return goquery.NewDocumentFromReader(resp.Body)
+}
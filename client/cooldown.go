@@ -0,0 +1,26 @@
+package client
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// The time until answers can be sent again is kept here so other runs wait as well
+const cooldownName = ".cooldown"
+
+func loadCooldown(dir string) time.Time {
+	data, err := os.ReadFile(dir + "/" + cooldownName)
+	if err != nil {
+		return time.Time{}
+	}
+	until, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}
+	}
+	return until
+}
+
+func saveCooldown(dir string, until time.Time) error {
+	return os.WriteFile(dir+"/"+cooldownName, []byte(until.Format(time.RFC3339)+"\n"), 0644)
+}
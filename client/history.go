@@ -0,0 +1,119 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+)
+
+const historyName = "answers.json"
+
+// Attempt is an answer that was submitted along with what the server thought of it
+type Attempt struct {
+	Level   int       `json:"level"`
+	Answer  string    `json:"answer"`
+	Verdict Verdict   `json:"verdict"`
+	Time    time.Time `json:"time"`
+}
+
+// History is every answer submitted for a day
+type History []Attempt
+
+func (v Verdict) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+func (v *Verdict) UnmarshalText(text []byte) error {
+	for verdict := Unknown; verdict <= WrongLevel; verdict++ {
+		if verdict.String() == string(text) {
+			*v = verdict
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown verdict %q", text)
+}
+
+// LoadHistory reads the answers submitted for the day in dir
+func LoadHistory(dir string) (History, error) {
+	data, err := os.ReadFile(dir + "/" + historyName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	history := History{}
+	err = json.Unmarshal(data, &history)
+	return history, err
+}
+
+func (h History) save(dir string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dir+"/"+historyName, append(data, '\n'), 0644)
+}
+
+// Check returns the verdict for an answer if it is already known without asking the server
+func (h History) Check(level int, answer string) (Result, bool) {
+	var low, high *big.Int
+	value, isNumber := new(big.Int).SetString(answer, 10)
+
+	for _, attempt := range h {
+		if attempt.Level != level {
+			continue
+		}
+
+		switch attempt.Verdict {
+		case Correct:
+			return Result{Verdict: AlreadySolved, Message: "Already solved with " + attempt.Answer, Local: true}, true
+		case Wrong, TooHigh, TooLow:
+			if attempt.Answer == answer {
+				return Result{Verdict: attempt.Verdict, Message: "Already submitted " + answer, Local: true}, true
+			}
+		}
+
+		// Keep track of the tightest bounds the hints give
+		n, ok := new(big.Int).SetString(attempt.Answer, 10)
+		if !ok {
+			continue
+		}
+		if attempt.Verdict == TooHigh && (high == nil || n.Cmp(high) < 0) {
+			high = n
+		} else if attempt.Verdict == TooLow && (low == nil || n.Cmp(low) > 0) {
+			low = n
+		}
+	}
+
+	if isNumber && high != nil && value.Cmp(high) >= 0 {
+		return Result{Verdict: TooHigh, Message: fmt.Sprintf("%s is not below %s, which was too high", answer, high), Local: true}, true
+	} else if isNumber && low != nil && value.Cmp(low) <= 0 {
+		return Result{Verdict: TooLow, Message: fmt.Sprintf("%s is not above %s, which was too low", answer, low), Local: true}, true
+	}
+	return Result{}, false
+}
+
+// Record adds the server's verdict on an answer to the history in dir
+func (h *History) Record(dir string, level int, answer string, result Result) error {
+	*h = append(*h, Attempt{
+		Level:   level,
+		Answer:  answer,
+		Verdict: result.Verdict,
+		Time:    time.Now(),
+	})
+	return h.save(dir)
+}
+
+// Correct returns the right answer for a level if it has been found
+func (h History) Correct(level int) (string, bool) {
+	for _, attempt := range h {
+		if attempt.Level == level && attempt.Verdict == Correct {
+			return attempt.Answer, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// SessionEnv is the environment variable holding the session cookie
+	SessionEnv = "AOC_SESSION"
+	// authName is the file holding the session cookie in the repository
+	authName = "auth.txt"
+)
+
+// ErrLoggedOut is returned for any request the site refuses because the session is missing or expired
+var ErrLoggedOut = errors.New("the session is missing or has expired, log in to the site again and run `aoc login` with the new session cookie")
+
+// errEmptySession is returned for a session file with nothing in it, which is passed over like a missing one
+var errEmptySession = errors.New("is empty")
+
+// loginMessage is in the body of every page the site refuses to logged out users
+const loginMessage = "Please log in"
+
+// ConfigPath is where the session is stored, under the user's config directory ($XDG_CONFIG_HOME on Linux)
+func ConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "session"), nil
+}
+
+// Session finds the session cookie in SessionEnv, the config file from ConfigPath,
+// or auth.txt in the working directory or one of its parents, in that order.
+// An empty config file is skipped like a missing one.
+func Session() (string, error) {
+	if session := strings.TrimSpace(os.Getenv(SessionEnv)); session != "" {
+		return session, nil
+	}
+
+	if name, err := ConfigPath(); err == nil {
+		if session, err := readSession(name); err == nil {
+			return session, nil
+		} else if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, errEmptySession) {
+			return "", err
+		}
+	}
+
+	name, err := findAuth()
+	if err != nil {
+		return "", err
+	}
+	return readSession(name)
+}
+
+// SaveSession stores the session in the config file from ConfigPath
+func SaveSession(session string) error {
+	name, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(name, []byte(strings.TrimSpace(session)+"\n"), 0600)
+}
+
+func readSession(name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	session := strings.TrimSpace(string(data))
+	if session == "" {
+		return "", fmt.Errorf("%s %w", name, errEmptySession)
+	}
+	return session, nil
+}
+
+// findAuth looks for auth.txt in the working directory and then its parents
+func findAuth() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		name := filepath.Join(dir, authName)
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("no session found, run `aoc login`, set " + SessionEnv + " or add it to " + authName)
+		}
+		dir = parent
+	}
+}
+
+// LoggedIn checks the session is accepted by loading the settings page, which needs a login
+func (c *Client) LoggedIn() (bool, error) {
+	resp, err := c.Get(c.URL + "/settings")
+	if errors.Is(err, ErrLoggedOut) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	// Logged out users are sent somewhere else or refused
+	if resp.StatusCode != 200 || resp.Request.URL.Path != "/settings" {
+		return false, nil
+	}
+
+	// The header shows who is logged in
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	return doc.Find(".user").Length() > 0, nil
+}
+
+// CheckSession returns ErrLoggedOut unless the session is accepted
+func (c *Client) CheckSession() error {
+	ok, err := c.LoggedIn()
+	if err != nil {
+		return err
+	} else if !ok {
+		return ErrLoggedOut
+	}
+	return nil
+}
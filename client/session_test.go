@@ -0,0 +1,112 @@
+package client_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aoc/client"
+)
+
+func TestSession(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		config string
+		auth   string
+		want   string
+	}{
+		{name: "environment first", env: "env\n", config: "config\n", auth: "auth\n", want: "env"},
+		{name: "then the config file", config: " config\n", auth: "auth\n", want: "config"},
+		{name: "then auth.txt", auth: "auth\n", want: "auth"},
+		{name: "blank environment", env: "  ", config: "config", want: "config"},
+		{name: "empty config file", config: "\n", auth: "auth\n", want: "auth"},
+		{name: "nothing"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(client.SessionEnv, test.env)
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			dir := t.TempDir()
+			chdir(t, dir)
+
+			if test.config != "" {
+				name, err := client.ConfigPath()
+				if err != nil {
+					t.Fatal(err)
+				}
+				writeFile(t, name, test.config)
+			}
+			if test.auth != "" {
+				writeFile(t, filepath.Join(dir, "auth.txt"), test.auth)
+			}
+
+			session, err := client.Session()
+			if test.want == "" {
+				if err == nil {
+					t.Errorf("Session = %q, want an error", session)
+				}
+				return
+			}
+			if err != nil || session != test.want {
+				t.Errorf("Session = %q, %v, want %q", session, err, test.want)
+			}
+		})
+	}
+}
+
+func TestSessionAuthInParent(t *testing.T) {
+	t.Setenv(client.SessionEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "auth.txt"), "auth\n")
+	chdir(t, filepath.Join(dir, "2021", "1"))
+
+	if session, err := client.Session(); err != nil || session != "auth" {
+		t.Errorf("Session = %q, %v, want auth", session, err)
+	}
+}
+
+func TestSaveSession(t *testing.T) {
+	t.Setenv(client.SessionEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	chdir(t, t.TempDir())
+
+	if err := client.SaveSession(" saved\n"); err != nil {
+		t.Fatal(err)
+	}
+	if session, err := client.Session(); err != nil || session != "saved" {
+		t.Errorf("Session = %q, %v, want saved", session, err)
+	}
+	name, _ := client.ConfigPath()
+	if info, err := os.Stat(name); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("the session file isn't private: %v", err)
+	}
+}
+
+// chdir runs the rest of the test in dir, creating it if needed
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func writeFile(t *testing.T, name, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Verdict is how the server judged a submitted answer
+type Verdict int
+
+const (
+	Unknown Verdict = iota
+	Correct
+	Wrong
+	TooHigh
+	TooLow
+	RateLimited
+	AlreadySolved
+	WrongLevel
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Correct:
+		return "correct"
+	case Wrong:
+		return "wrong"
+	case TooHigh:
+		return "too high"
+	case TooLow:
+		return "too low"
+	case RateLimited:
+		return "rate limited"
+	case AlreadySolved:
+		return "already solved"
+	case WrongLevel:
+		return "wrong level"
+	}
+	return "unknown"
+}
+
+// Result is the response to a submitted answer
+type Result struct {
+	Verdict Verdict
+	// How long until another answer can be submitted when rate limited
+	Wait    time.Duration
+	Message string
+	// Whether the verdict came from the answer history instead of the server
+	Local bool
+}
+
+// ErrNotRecorded is returned along with a verdict from the server that couldn't be saved to the day,
+// so the answer was still judged
+var ErrNotRecorded = errors.New("the verdict couldn't be saved")
+
+var (
+	waitRegex    = regexp.MustCompile(`You have ([\dhms ]+) left to wait`)
+	penaltyRegex = regexp.MustCompile(`(?i)please wait (one|\d+) minutes?`)
+)
+
+// String returns the verdict along with the message explaining it
+func (r Result) String() string {
+	return r.Verdict.String() + ": " + r.Message
+}
+
+// Submit sends the answer for a level of the day in dir, which is laid out as YEAR/DAY
+func (c *Client) Submit(dir string, level int, answer string) (Result, error) {
+	// Get the year and day from the path
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Result{}, err
+	}
+	day := filepath.Base(abs)
+	year := filepath.Base(filepath.Dir(abs))
+
+	// Don't send answers that are already known to be wrong
+	history, err := LoadHistory(dir)
+	if err != nil {
+		return Result{}, err
+	}
+	if result, ok := history.Check(level, answer); ok {
+		return result, nil
+	}
+
+	// Wait for the cooldown from an earlier answer, even one sent by another run
+	if until := loadCooldown(dir); time.Now().Before(until) {
+		return Result{
+			Verdict: RateLimited,
+			// Round up so the wait is never cut short to nothing
+			Wait:    (time.Until(until) + time.Second - 1).Truncate(time.Second),
+			Message: "Still waiting for the cooldown from an earlier answer",
+			Local:   true,
+		}, nil
+	}
+
+	// Make sure the verdict can be saved before the answer is sent
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Result{}, err
+	}
+
+	// Submit the answer
+	resp, err := c.PostForm(fmt.Sprintf("%s/%s/day/%s/answer", c.URL, year, day), url.Values{
+		"level":  {strconv.Itoa(level)},
+		"answer": {answer},
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return Result{}, fmt.Errorf("submitting %s/%s: %s", year, day, resp.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := parseResult(doc.Find("main article p").First().Text())
+	if result.Verdict == WrongLevel {
+		// The server says the same thing for a solved level and one that isn't unlocked yet,
+		// and the level may have been solved since the page was cached
+		page := fmt.Sprintf("%s/%s/day/%s", c.URL, year, day)
+		c.Forget(page)
+		doc, err := c.Document(page)
+		if err != nil {
+			return result, err
+		}
+		if doc.Find("main p:contains('Your puzzle answer was')").Length() >= level {
+			result.Verdict = AlreadySolved
+		}
+	}
+
+	// Solving a part changes the puzzle page and the calendar
+	if result.Verdict == Correct {
+		c.Forget(fmt.Sprintf("%s/%s/day/%s", c.URL, year, day))
+		c.Forget(fmt.Sprintf("%s/%s", c.URL, year))
+	}
+
+	// Remember how long the server wants us to wait before the next answer
+	if result.Wait > 0 {
+		if err := saveCooldown(dir, time.Now().Add(result.Wait)); err != nil {
+			return result, fmt.Errorf("%w: %v", ErrNotRecorded, err)
+		}
+	}
+
+	// Only keep answers the server actually judged
+	if result.Verdict == RateLimited {
+		return result, nil
+	}
+	if err := history.Record(dir, level, answer, result); err != nil {
+		return result, fmt.Errorf("%w: %v", ErrNotRecorded, err)
+	}
+	return result, nil
+}
+
+// SubmitWait submits an answer like Submit, waiting out any cooldown and trying again until it is judged.
+// wait is called with each cooldown to wait it out, such as to show a countdown, or it can be nil to just sleep.
+// A rate limit without a wait that can be read from the server's message is returned rather than retried.
+func (c *Client) SubmitWait(dir string, level int, answer string, wait func(time.Duration)) (Result, error) {
+	if wait == nil {
+		wait = time.Sleep
+	}
+	for {
+		result, err := c.Submit(dir, level, answer)
+		if err != nil || result.Verdict != RateLimited || result.Wait <= 0 {
+			return result, err
+		}
+		wait(result.Wait)
+	}
+}
+
+// parseResult works out the verdict from the message the server responds with
+func parseResult(message string) Result {
+	message = strings.TrimSpace(message)
+	result := Result{Message: message}
+
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		result.Verdict = Correct
+	case strings.Contains(message, "your answer is too high"):
+		result.Verdict = TooHigh
+	case strings.Contains(message, "your answer is too low"):
+		result.Verdict = TooLow
+	case strings.Contains(message, "not the right answer"):
+		result.Verdict = Wrong
+	case strings.Contains(message, "You gave an answer too recently"):
+		result.Verdict = RateLimited
+		if match := waitRegex.FindStringSubmatch(message); match != nil {
+			result.Wait, _ = time.ParseDuration(strings.ReplaceAll(match[1], " ", ""))
+		}
+	case strings.Contains(message, "You don't seem to be solving the right level"):
+		result.Verdict = WrongLevel
+	}
+
+	// Wrong answers come with a penalty before the next one
+	if match := penaltyRegex.FindStringSubmatch(message); match != nil {
+		minutes, err := strconv.Atoi(match[1])
+		if err != nil {
+			minutes = 1
+		}
+		result.Wait = time.Duration(minutes) * time.Minute
+	}
+	return result
+}
@@ -0,0 +1,266 @@
+package client_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aoc/aoctest"
+	"aoc/client"
+)
+
+// newSite starts a fake site with one puzzle and a client logged in to it
+func newSite(t *testing.T) (*aoctest.Server, *client.Client) {
+	t.Helper()
+	client.RequestInterval = 0
+
+	s := aoctest.NewServer()
+	t.Cleanup(s.Close)
+	s.Session = "session"
+	s.Penalty = 0
+	s.AddPuzzle(2030, 1, aoctest.Puzzle{
+		Title:   "Test",
+		Parts:   [2]string{"<p>Part one</p>", "<p>Part two</p>"},
+		Input:   "1\n2\n",
+		Answers: [2]string{"15", "30"},
+	})
+
+	c, err := client.NewWithSession(s.URL, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CacheDir = t.TempDir()
+	return s, c
+}
+
+// dayDir returns a directory for the day, which Submit gets the year and day from
+func dayDir(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "2030", "1")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestSubmitVerdicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		solved int
+		level  int
+		answer string
+		want   client.Verdict
+	}{
+		{"right", 0, 1, "15", client.Correct},
+		{"wrong", 0, 1, "fifteen", client.Wrong},
+		{"too high", 0, 1, "20", client.TooHigh},
+		{"too low", 0, 1, "3", client.TooLow},
+		{"right part two", 1, 2, "30", client.Correct},
+		{"part two before part one", 0, 2, "30", client.WrongLevel},
+		{"already solved", 1, 1, "15", client.AlreadySolved},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, c := newSite(t)
+			s.SetSolved(2030, 1, test.solved)
+
+			result, err := c.Submit(dayDir(t), test.level, test.answer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Verdict != test.want || result.Local {
+				t.Errorf("verdict = %v (local %v), want %v from the server", result.Verdict, result.Local, test.want)
+			}
+			if test.want == client.Correct && s.Solved(2030, 1) != test.level {
+				t.Errorf("server has %d parts solved, want %d", s.Solved(2030, 1), test.level)
+			}
+		})
+	}
+}
+
+func TestSubmitCreatesDay(t *testing.T) {
+	s, c := newSite(t)
+	dir := filepath.Join(t.TempDir(), "2030", "1")
+
+	result, err := c.Submit(dir, 1, "15")
+	if err != nil || result.Verdict != client.Correct {
+		t.Fatalf("Submit = %v, %v, want correct", result.Verdict, err)
+	}
+	if s.Solved(2030, 1) != 1 {
+		t.Error("the answer wasn't sent")
+	}
+	if history, err := client.LoadHistory(dir); err != nil || len(history) != 1 {
+		t.Errorf("history = %v, %v, want the answer", history, err)
+	}
+}
+
+func TestSubmitNotRecorded(t *testing.T) {
+	s, c := newSite(t)
+	s.Penalty = time.Minute
+	dir := dayDir(t)
+
+	// The cooldown can't be written over a directory
+	if err := os.Mkdir(filepath.Join(dir, ".cooldown"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.Submit(dir, 1, "3")
+	if !errors.Is(err, client.ErrNotRecorded) {
+		t.Errorf("error = %v, want ErrNotRecorded", err)
+	}
+	if result.Verdict != client.TooLow {
+		t.Errorf("verdict = %v, want the server's too low", result.Verdict)
+	}
+}
+
+func TestSubmitSolvedElsewhere(t *testing.T) {
+	s, c := newSite(t)
+
+	// The page is cached before part one is solved, such as from a browser
+	if _, err := c.Document(s.URL + "/2030/day/1"); err != nil {
+		t.Fatal(err)
+	}
+	s.SetSolved(2030, 1, 1)
+
+	result, err := c.Submit(dayDir(t), 1, "15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != client.AlreadySolved {
+		t.Errorf("verdict = %v, want already solved", result.Verdict)
+	}
+}
+
+func TestSubmitCooldown(t *testing.T) {
+	s, c := newSite(t)
+	s.Penalty = time.Minute
+	dir := dayDir(t)
+
+	if result, err := c.Submit(dir, 1, "3"); err != nil || result.Verdict != client.TooLow {
+		t.Fatalf("first answer = %v, %v, want too low", result.Verdict, err)
+	}
+
+	// The wait is remembered, so the next answer isn't sent
+	result, err := c.Submit(dir, 1, "14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != client.RateLimited || !result.Local || result.Wait <= 0 {
+		t.Errorf("second answer = %+v, want a local wait", result)
+	}
+
+	// Without the saved wait the server refuses it
+	if err := os.Remove(filepath.Join(dir, ".cooldown")); err != nil {
+		t.Fatal(err)
+	}
+	result, err = c.Submit(dir, 1, "14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != client.RateLimited || result.Local || result.Wait <= 0 {
+		t.Errorf("answer during the server's cooldown = %+v, want a wait from the server", result)
+	}
+}
+
+func TestSubmitShortCooldown(t *testing.T) {
+	_, c := newSite(t)
+	dir := dayDir(t)
+
+	// Less than a second left still waits a whole one
+	until := time.Now().Truncate(time.Second).Add(time.Second)
+	if err := os.WriteFile(filepath.Join(dir, ".cooldown"), []byte(until.Format(time.RFC3339)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.Submit(dir, 1, "15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != client.RateLimited || result.Wait != time.Second {
+		t.Errorf("result = %+v, want a wait of 1s", result)
+	}
+}
+
+func TestSubmitWaitUnknownWait(t *testing.T) {
+	client.RequestInterval = 0
+
+	// A rate limit in words the wait can't be read from
+	posts := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		io.WriteString(w, "<main><article><p>You gave an answer too recently. Please come back later.</p></article></main>")
+	}))
+	t.Cleanup(s.Close)
+	c, err := client.NewWithSession(s.URL, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CacheDir = ""
+
+	waits := 0
+	result, err := c.SubmitWait(dayDir(t), 1, "15", func(time.Duration) { waits++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != client.RateLimited || posts != 1 || waits != 0 {
+		t.Errorf("SubmitWait = %v after %d answers and %d waits, want to give up after one", result.Verdict, posts, waits)
+	}
+}
+
+func TestSubmitHistory(t *testing.T) {
+	s, c := newSite(t)
+	dir := dayDir(t)
+
+	if _, err := c.Submit(dir, 1, "20"); err != nil {
+		t.Fatal(err)
+	}
+	before := s.Requests("/2030/day/1/answer")
+
+	// Answers at or above a "too high" are refused without asking the server
+	for _, answer := range []string{"20", "25"} {
+		result, err := c.Submit(dir, 1, answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Local || result.Verdict != client.TooHigh {
+			t.Errorf("%s = %+v, want a local too high", answer, result)
+		}
+	}
+	if after := s.Requests("/2030/day/1/answer"); after != before {
+		t.Errorf("sent %d more answers, want none", after-before)
+	}
+}
+
+func TestSubmitLoggedOut(t *testing.T) {
+	s, _ := newSite(t)
+	c, err := client.NewWithSession(s.URL, "expired")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CacheDir = t.TempDir()
+
+	if _, err := c.Submit(dayDir(t), 1, "15"); !errors.Is(err, client.ErrLoggedOut) {
+		t.Errorf("error = %v, want ErrLoggedOut", err)
+	}
+}
+
+func TestServerRejectsBadLevels(t *testing.T) {
+	s, c := newSite(t)
+	s.SetSolved(2030, 1, 2)
+
+	for _, level := range []string{"0", "3", ""} {
+		resp, err := c.PostForm(s.URL+"/2030/day/1/answer", url.Values{"level": {level}, "answer": {"15"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("level %q got %s, want 400", level, resp.Status)
+		}
+	}
+}
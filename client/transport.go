@@ -0,0 +1,196 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserAgentEnv is the environment variable holding contact details, such as an email address,
+// to send along with every request so the site knows who to ask about this tool
+const UserAgentEnv = "AOC_USER_AGENT"
+
+// defaultUserAgent is sent when neither the repository nor any contact details are known
+const defaultUserAgent = "aoc (Advent of Code fetcher and submitter)"
+
+var (
+	// RequestInterval is the shortest time between two requests, shared by every client
+	RequestInterval = time.Second
+	// MaxRetries is how many times a request is tried again after a server error
+	MaxRetries = 4
+	// RetryDelay is how long to wait before the first retry, doubling for each one after
+	RetryDelay = time.Second
+	// AttemptTimeout is how long each attempt has to get the whole response, body included
+	AttemptTimeout = time.Minute
+)
+
+// Repository is where this tool lives, which the User-Agent points to. It can be set when building with
+// -ldflags "-X aoc/client.Repository=URL", otherwise it's the origin remote of the git clone it runs in.
+var Repository string
+
+var repositoryOnce sync.Once
+
+// repository returns Repository, looking it up from git the first time if it isn't set
+func repository() string {
+	repositoryOnce.Do(func() {
+		if Repository != "" {
+			return
+		}
+		out, err := exec.Command("git", "config", "--get", "remote.origin.url").Output()
+		if err == nil {
+			Repository = publicURL(string(out))
+		}
+	})
+	return Repository
+}
+
+// publicURL turns a git remote into a web address without any credentials in it,
+// or returns "" if it isn't on a host, such as a local path
+func publicURL(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSpace(remote), ".git")
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		u.User = nil
+		u.Scheme = "https"
+		return u.String()
+	}
+
+	// Remotes like git@github.com:owner/repo
+	if at := strings.Index(remote, "@"); at >= 0 {
+		remote = remote[at+1:]
+	}
+	host, path, ok := strings.Cut(remote, ":")
+	if !ok || host == "" || strings.Contains(host, "/") {
+		return ""
+	}
+	return "https://" + host + "/" + strings.TrimPrefix(path, "/")
+}
+
+// UserAgent returns the User-Agent to send, pointing to the repository and with the contact details
+// from UserAgentEnv if set
+func UserAgent() string {
+	var details []string
+	if repo := repository(); repo != "" {
+		details = append(details, "+"+repo)
+	}
+	if contact := os.Getenv(UserAgentEnv); contact != "" {
+		details = append(details, contact)
+	}
+	if len(details) == 0 {
+		return defaultUserAgent
+	}
+	return "aoc (" + strings.Join(details, "; ") + ")"
+}
+
+// limiter spaces out requests across every client in the program
+var limiter = &rateLimiter{}
+
+type rateLimiter struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the next request may be sent
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(RequestInterval)
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
+
+// newTransport times out each attempt rather than the whole request, so time spent
+// waiting for the rate limiter or between retries doesn't count against it.
+// fetch gives each attempt AttemptTimeout on top of these, which also covers reading the body.
+func newTransport(c *Client) *transport {
+	next := http.DefaultTransport.(*http.Transport).Clone()
+	next.DialContext = (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	next.TLSHandshakeTimeout = 10 * time.Second
+	next.ResponseHeaderTimeout = 30 * time.Second
+	return &transport{client: c, next: next}
+}
+
+// transport identifies and throttles every request, tries again on server errors,
+// answers what it can from the cache, and turns the site's "Please log in" pages into ErrLoggedOut so an error page
+// is never mistaken for an input or puzzle
+type transport struct {
+	client *Client
+	next   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.client.UserAgent)
+	return t.client.cached(req, t.fetch)
+}
+
+// fetch sends the request to the site, waiting its turn and trying again after server errors
+func (t *transport) fetch(req *http.Request) (*http.Response, error) {
+	delay := RetryDelay
+	for attempt := 0; ; attempt++ {
+		limiter.wait()
+		ctx, cancel := context.WithTimeout(req.Context(), AttemptTimeout)
+		resp, err := t.next.RoundTrip(req.WithContext(ctx))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		if resp.StatusCode < 400 {
+			// The deadline lasts until the caller is done with the body
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		// Error pages are short, so read the body and put it back
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Contains(data, []byte(loginMessage)) {
+			return nil, ErrLoggedOut
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+
+		// An answer may have been judged before the error, so only safe requests are sent again
+		if resp.StatusCode < 500 || attempt >= MaxRetries || !retryable(req) {
+			return resp, nil
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		delay *= 2
+	}
+}
+
+// cancelBody cancels the context of an attempt once its body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func retryable(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead
+}
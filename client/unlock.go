@@ -0,0 +1,21 @@
+package client
+
+import "time"
+
+// UnlockZone is the zone puzzles unlock at midnight in, which the event always treats as UTC-5
+var UnlockZone = time.FixedZone("EST", -5*60*60)
+
+// UnlockTime returns when a day's puzzle unlocks
+func UnlockTime(year, day int) time.Time {
+	return time.Date(year, time.December, day, 0, 0, 0, 0, UnlockZone)
+}
+
+// NextUnlock returns the next day of the year to unlock, or 0 if they all have
+func NextUnlock(year int, now time.Time) int {
+	for day := 1; day <= 25; day++ {
+		if UnlockTime(year, day).After(now) {
+			return day
+		}
+	}
+	return 0
+}
@@ -0,0 +1,589 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aoc/client"
+	"aoc/registry"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string)
+}
+
+var commands = []*command{
+	{"login", "check a session cookie and save it for later", runLogin},
+	{"init", "create the directories, inputs and templates for a year", runInit},
+	{"fetch", "download the input and description for a day", runFetch},
+	{"submit", "submit an answer for a day", runSubmit},
+	{"run", "run the solutions for a day or a whole year", runRun},
+	{"verify", "check the solutions still give the recorded answers", runVerify},
+	{"bench", "time the parser and both parts of each day", runBench},
+	{"status", "show the stars collected for a year", runStatus},
+	{"leaderboard", "show a private leaderboard", runLeaderboard},
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: aoc <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'aoc <command> -h' for the flags of a command.")
+}
+
+// options holds the flags shared between the commands
+type options struct {
+	year      int
+	day       int
+	force     bool
+	url       string
+	session   string
+	userAgent string
+	refresh   bool
+	template  string
+}
+
+func newFlagSet(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.IntVar(&opts.year, "year", time.Now().Year(), "the year of the event")
+	fs.IntVar(&opts.day, "day", 0, "the day of the puzzle (defaults to today during the event)")
+	fs.StringVar(&opts.url, "url", client.BaseURL(), "the site to talk to (or set "+client.URLEnv+")")
+	fs.StringVar(&opts.userAgent, "user-agent", client.UserAgent(), "the User-Agent sent to the site, with your contact details (or set "+client.UserAgentEnv+")")
+	fs.BoolVar(&opts.refresh, "refresh", false, "check cached pages with the site even if they should still be fresh")
+	fs.StringVar(&opts.session, "session", "", "the session cookie, instead of "+client.SessionEnv+", the config file or auth.txt")
+	return fs
+}
+
+func defaultDay(year int) int {
+	// During the event assume today's puzzle
+	now := time.Now()
+	if now.Year() == year && now.Month() == time.December && now.Day() <= 25 {
+		return now.Day()
+	}
+	return 0
+}
+
+func (opts *options) yearStr() string {
+	return strconv.Itoa(opts.year)
+}
+
+func (opts *options) dayStr() string {
+	return strconv.Itoa(opts.day)
+}
+
+func (opts *options) dayDir() string {
+	return fmt.Sprintf("%d/%d", opts.year, opts.day)
+}
+
+// requireDay exits if no valid day was given or can be assumed
+func (opts *options) requireDay(fs *flag.FlagSet) {
+	if opts.day == 0 {
+		opts.day = defaultDay(opts.year)
+	}
+	if opts.day < 1 || opts.day > 25 {
+		fmt.Fprintln(os.Stderr, "a day between 1 and 25 is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+func runLogin(args []string) {
+	opts := &options{}
+	fs := newFlagSet("login", opts)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc login [flags] [session]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	session := opts.session
+	if fs.NArg() > 0 {
+		session = fs.Arg(0)
+	}
+	if session == "" {
+		// Ask for it so it doesn't end up in the shell history
+		fmt.Fprint(os.Stderr, "Session cookie: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatalln(err)
+		}
+		session = strings.TrimSpace(line)
+	}
+
+	c, err := client.NewWithSession(opts.url, session)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	c.UserAgent = opts.userAgent
+	ok, err := c.LoggedIn()
+	if err != nil {
+		log.Fatalln(err)
+	} else if !ok {
+		log.Fatalln("the session was not accepted, copy the session cookie from a logged in browser")
+	}
+
+	if err := client.SaveSession(session); err != nil {
+		log.Fatalln(err)
+	}
+	name, _ := client.ConfigPath()
+	log.Println("Logged in, saved the session to", name)
+}
+
+func runInit(args []string) {
+	opts := &options{}
+	fs := newFlagSet("init", opts)
+	fs.BoolVar(&opts.force, "force", false, "download inputs again and replace existing solutions with the template")
+	fs.Lookup("day").Usage = "only set up this day instead of every unlocked day"
+	wait := fs.Bool("wait", false, "wait for the day to unlock and set it up as soon as it does")
+	tests := fs.Bool("tests", false, "only add the example test to existing days missing one")
+	jobs := fs.Int("jobs", 4, "how many days to set up at once")
+	fs.StringVar(&opts.template, "template", autoTemplate, "the template for new solutions, one of "+strings.Join(templateNames(), ", ")+" or "+autoTemplate+" to pick one from the input")
+	fs.Parse(args)
+
+	if *jobs < 1 {
+		log.Fatalln("--jobs must be at least 1")
+	}
+
+	if *tests {
+		if err := addTests(opts.yearStr()); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	if opts.template != autoTemplate {
+		if _, _, _, err := findTemplate(opts.template); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	client := makeClient(opts)
+	if *wait {
+		waitAndInitialize(client, fs, opts)
+		return
+	}
+
+	year := opts.yearStr()
+
+	var days []string
+	if opts.day != 0 {
+		// Only set up the one day without looking at the calendar
+		opts.requireDay(fs)
+		days = []string{opts.dayStr()}
+	} else {
+		// Get the days of this year
+		days = getDays(client, year)
+	}
+
+	// Get all inputs if they don't exist
+	failed := initializeDays(client, year, days, opts.force, opts.template, *jobs)
+
+	// Let the runner know about any new days, even if some failed
+	if err := updateImports(); err != nil {
+		log.Fatalln(err)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runFetch(args []string) {
+	opts := &options{}
+	fs := newFlagSet("fetch", opts)
+	fs.BoolVar(&opts.force, "force", false, "download the input again even if it exists")
+	fs.Parse(args)
+	opts.requireDay(fs)
+
+	err := os.MkdirAll(opts.dayDir(), 0755)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	client := makeClient(opts)
+	// Save the puzzle every time since part two shows up after solving part one,
+	// which may have been on the site rather than with submit
+	client.Refresh = true
+	if err := savePuzzle(client, opts.yearStr(), opts.dayStr(), opts.dayDir()); err != nil {
+		log.Fatalln(err)
+	}
+	log.Println("Saved the puzzle for", opts.dayDir())
+
+	inputName := opts.dayDir() + "/input.txt"
+	if _, err := os.Stat(inputName); err == nil && !opts.force {
+		log.Println(inputName, "already exists")
+		return
+	}
+
+	if err := downloadInput(client, opts.yearStr(), opts.dayStr(), inputName); err != nil {
+		log.Fatalln(err)
+	}
+	log.Println("Saved", inputName)
+}
+
+func runSubmit(args []string) {
+	opts := &options{}
+	fs := newFlagSet("submit", opts)
+	level := fs.Int("level", 1, "the part of the puzzle the answer is for (1 or 2)")
+	wait := fs.Bool("wait", false, "wait out the cooldown and submit again when rate limited")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc submit [flags] <answer>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	opts.requireDay(fs)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *level != 1 && *level != 2 {
+		log.Println("level must be 1 or 2")
+		os.Exit(2)
+	}
+
+	c := makeClient(opts)
+	var result client.Result
+	var err error
+	if *wait {
+		result, err = c.SubmitWait(opts.dayDir(), *level, fs.Arg(0), func(wait time.Duration) {
+			countdown(time.Now().Add(wait), "Submitting again")
+		})
+	} else {
+		result, err = c.Submit(opts.dayDir(), *level, fs.Arg(0))
+	}
+	if errors.Is(err, client.ErrNotRecorded) {
+		// The answer was judged, so still report the verdict
+		log.Println("Warning:", err)
+	} else if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Print the verdict on its own and exit with a code scripts can check
+	if result.Local {
+		log.Println(result.Message, "(not submitted)")
+	} else {
+		log.Println(result.Message)
+	}
+	if result.Verdict == client.RateLimited && result.Wait > 0 {
+		log.Println("You can submit again in", result.Wait)
+	}
+	fmt.Println(result.Verdict)
+	os.Exit(verdictCodes[result.Verdict])
+}
+
+// verdictCodes are the exit codes of submit for each verdict, skipping 1 for errors,
+// which mean nothing was judged, and 2 for bad usage
+var verdictCodes = map[client.Verdict]int{
+	client.Correct:       0,
+	client.Wrong:         9,
+	client.TooHigh:       3,
+	client.TooLow:        4,
+	client.RateLimited:   5,
+	client.AlreadySolved: 6,
+	client.WrongLevel:    7,
+	client.Unknown:       8,
+}
+
+func runRun(args []string) {
+	opts := &options{}
+	fs := newFlagSet("run", opts)
+	fs.Lookup("day").Usage = "the day to run, or every day of the year if not given"
+	part := fs.Int("part", 0, "only run this part (1 or 2)")
+	example := fs.Bool("example", false, "use example.txt instead of input.txt")
+	fs.Parse(args)
+
+	days := selectDays(opts)
+	if len(days) == 0 {
+		log.Fatalln("no solutions registered for", opts.year)
+	}
+
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+
+	inputName := "input.txt"
+	if *example {
+		inputName = "example.txt"
+	}
+
+	for _, s := range days {
+		for _, p := range parts {
+			answer, parse, solve, err := runPart(s, p, s.Dir()+"/"+inputName)
+			if errors.Is(err, os.ErrNotExist) {
+				log.Println("Skipping", s.Dir(), "since it has no", inputName)
+				break
+			} else if err != nil {
+				log.Fatalln(err)
+			}
+			fmt.Printf("%s part %d: %v\t(parse %v, solve %v)\n", s.Dir(), p, answer, parse, solve)
+		}
+	}
+}
+
+// selectDays returns the solution of the day, or of every day of the year if no day is given.
+// Days solved in another language can't be run, so they are pointed out instead.
+func selectDays(opts *options) []*registry.Solution {
+	if opts.day != 0 {
+		s, ok := registry.Get(opts.year, opts.day)
+		if !ok {
+			if name := otherSolution(opts.dayDir()); name != "" {
+				log.Fatalf("%s is solved in %s, which has to be run on its own\n", opts.dayDir(), name)
+			}
+			log.Fatalf("no solution registered for %s\n", opts.dayDir())
+		}
+		return []*registry.Solution{s}
+	}
+
+	dirs, _ := numberedDirs(opts.yearStr())
+	for _, day := range dirs {
+		dir := fmt.Sprintf("%d/%d", opts.year, day)
+		if _, ok := registry.Get(opts.year, day); !ok {
+			if name := otherSolution(dir); name != "" {
+				log.Println("Skipping", dir, "since it is solved in", name)
+			}
+		}
+	}
+	return registry.Days(opts.year)
+}
+
+// runPart parses the input fresh for the part, since some parts change their input
+func runPart(s *registry.Solution, part int, inputName string) (answer interface{}, parse, solve time.Duration, err error) {
+	if part < 1 || part > len(s.Parts) {
+		return nil, 0, 0, fmt.Errorf("there is no part %d", part)
+	}
+
+	file, err := os.Open(inputName)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer file.Close()
+
+	start := time.Now()
+	input := s.Read(file)
+	parse = time.Since(start)
+
+	start = time.Now()
+	answer = s.Parts[part-1](input)
+	solve = time.Since(start)
+	return answer, parse, solve, nil
+}
+
+func runVerify(args []string) {
+	opts := &options{}
+	fs := newFlagSet("verify", opts)
+	fs.Lookup("day").Usage = "the day to verify, or every day of the year if not given"
+	all := fs.Bool("all", false, "verify every year instead of just one")
+	fs.Parse(args)
+
+	var days []*registry.Solution
+	if *all {
+		if opts.day != 0 {
+			log.Fatalln("--all verifies every day, so it can't be used with --day")
+		}
+		// Go through each year like one given with --year, so days in other languages are pointed out
+		years, err := numberedDirs(".")
+		if err != nil {
+			log.Fatalln(err)
+		}
+		for _, year := range years {
+			opts.year = year
+			days = append(days, selectDays(opts)...)
+		}
+	} else {
+		days = selectDays(opts)
+	}
+
+	failed := 0
+	for _, s := range days {
+		history, err := client.LoadHistory(s.Dir())
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		for part := 1; part <= len(s.Parts); part++ {
+			want, ok := history.Correct(part)
+			if !ok {
+				fmt.Printf("%s part %d: no recorded answer\n", s.Dir(), part)
+				continue
+			}
+
+			answer, _, _, err := runPart(s, part, s.Dir()+"/input.txt")
+			if err != nil {
+				fmt.Printf("%s part %d: FAIL %v\n", s.Dir(), part, err)
+				failed++
+				continue
+			}
+
+			if got := fmt.Sprint(answer); got != want {
+				fmt.Printf("%s part %d: FAIL got %s, want %s\n", s.Dir(), part, got, want)
+				failed++
+			} else {
+				fmt.Printf("%s part %d: ok\n", s.Dir(), part)
+			}
+		}
+	}
+
+	if failed > 0 {
+		log.Fatalln(failed, "answers changed")
+	}
+}
+
+func runBench(args []string) {
+	opts := &options{}
+	fs := newFlagSet("bench", opts)
+	fs.Lookup("day").Usage = "the day to benchmark, or every day of the year if not given"
+	n := fs.Int("n", 10, "the number of times to run each day")
+	example := fs.Bool("example", false, "use example.txt instead of input.txt")
+	jsonName := fs.String("json", "", "also write a JSON report to this file")
+	fs.Parse(args)
+
+	if *n < 1 {
+		log.Fatalln("-n must be at least 1")
+	}
+
+	days := selectDays(opts)
+
+	inputName := "input.txt"
+	if *example {
+		inputName = "example.txt"
+	}
+
+	report := benchReport{
+		Commit:    currentCommit(),
+		GoVersion: runtime.Version(),
+		Time:      time.Now(),
+		Input:     inputName,
+	}
+	for _, s := range days {
+		data, err := os.ReadFile(s.Dir() + "/" + inputName)
+		if errors.Is(err, os.ErrNotExist) {
+			log.Println("Skipping", s.Dir(), "since it has no", inputName)
+			continue
+		} else if err != nil {
+			log.Fatalln(err)
+		}
+		report.Results = append(report.Results, benchmark(s, data, *n))
+	}
+
+	printBenchTable(os.Stdout, report.Results)
+	if *jsonName != "" {
+		if err := writeBenchReport(*jsonName, report); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+func runStatus(args []string) {
+	opts := &options{}
+	fs := newFlagSet("status", opts)
+	fs.Parse(args)
+
+	client := makeClient(opts)
+	// Stars change whenever a part is solved
+	client.Refresh = true
+	doc, err := client.Document(client.URL + "/" + opts.yearStr())
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	total := 0
+	doc.Find(".calendar a").Each(func(i int, s *goquery.Selection) {
+		day := strings.TrimSpace(s.Find(".calendar-day").Text())
+		stars := 0
+		if s.HasClass("calendar-verycomplete") {
+			stars = 2
+		} else if s.HasClass("calendar-complete") {
+			stars = 1
+		}
+		total += stars
+		fmt.Printf("Day %2s: %-2s\n", day, strings.Repeat("*", stars))
+	})
+	fmt.Println("Total:", total)
+}
+
+// leaderboard is the JSON format of a private leaderboard
+type leaderboard struct {
+	Event   string `json:"event"`
+	OwnerID int    `json:"owner_id"`
+	Members map[string]struct {
+		Name       string `json:"name"`
+		ID         int    `json:"id"`
+		Stars      int    `json:"stars"`
+		LocalScore int    `json:"local_score"`
+	} `json:"members"`
+}
+
+func runLeaderboard(args []string) {
+	opts := &options{}
+	fs := newFlagSet("leaderboard", opts)
+	id := fs.String("id", "", "the id of the private leaderboard")
+	fs.Parse(args)
+
+	if *id == "" {
+		fmt.Fprintln(os.Stderr, "a leaderboard id is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	client := makeClient(opts)
+	urlStr := fmt.Sprintf("%s/%s/leaderboard/private/view/%s.json", client.URL, opts.yearStr(), *id)
+	resp, err := client.Get(urlStr)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		log.Fatalln(urlStr, resp.Status)
+	}
+
+	board := leaderboard{}
+	if err := json.NewDecoder(resp.Body).Decode(&board); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Sort the members by their score
+	ids := make([]string, 0, len(board.Members))
+	for k := range board.Members {
+		ids = append(ids, k)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return board.Members[ids[i]].LocalScore > board.Members[ids[j]].LocalScore
+	})
+
+	for i, k := range ids {
+		member := board.Members[k]
+		name := member.Name
+		if name == "" {
+			name = "(anonymous user #" + k + ")"
+		}
+		fmt.Printf("%3d) %5d %3d* %s\n", i+1, member.LocalScore, member.Stars, name)
+	}
+}
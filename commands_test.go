@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"aoc/client"
+)
+
+func TestVerdictCodes(t *testing.T) {
+	seen := map[int]client.Verdict{}
+	for verdict := client.Unknown; verdict <= client.WrongLevel; verdict++ {
+		code, ok := verdictCodes[verdict]
+		if !ok {
+			t.Errorf("%v has no exit code", verdict)
+			continue
+		}
+		// 1 is for errors and 2 for bad usage, so a script never mistakes them for a verdict
+		if code == 1 || code == 2 {
+			t.Errorf("%v exits with %d", verdict, code)
+		}
+		if other, ok := seen[code]; ok {
+			t.Errorf("%v and %v both exit with %d", verdict, other, code)
+		}
+		seen[code] = verdict
+	}
+}
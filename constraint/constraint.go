@@ -0,0 +1,148 @@
+// Package constraint finds one to one mappings, such as scrambled wires onto segments,
+// by propagating constraints and searching whatever they leave open.
+package constraint
+
+import "math/bits"
+
+// Set is a set of small values from 0 to 63
+type Set uint64
+
+// SetOf returns the set of the values
+func SetOf(values ...int) (s Set) {
+	for _, v := range values {
+		s |= 1 << v
+	}
+	return s
+}
+
+// Full returns the set of the values from 0 to n-1
+func Full(n int) Set {
+	return 1<<n - 1
+}
+
+// Has checks if v is in the set
+func (s Set) Has(v int) bool {
+	return s&(1<<v) != 0
+}
+
+// Len returns the number of values in the set
+func (s Set) Len() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// Values returns the values in the set from smallest to largest
+func (s Set) Values() []int {
+	values := make([]int, 0, s.Len())
+	for s != 0 {
+		v := bits.TrailingZeros64(uint64(s))
+		values = append(values, v)
+		s &^= 1 << v
+	}
+	return values
+}
+
+// Permutation maps each of n variables onto a different value from 0 to n-1.
+// Each variable starts out able to take any value until restricted.
+type Permutation struct {
+	domains []Set
+}
+
+// NewPermutation returns a permutation of n variables, where n is at most 64
+func NewPermutation(n int) *Permutation {
+	p := &Permutation{domains: make([]Set, n)}
+	for i := range p.domains {
+		p.domains[i] = Full(n)
+	}
+	return p
+}
+
+// Restrict only lets variable v take the allowed values
+func (p *Permutation) Restrict(v int, allowed Set) {
+	p.domains[v] &= allowed
+}
+
+// Domain returns the values variable v can still take
+func (p *Permutation) Domain(v int) Set {
+	return p.domains[v]
+}
+
+// Propagate narrows the domains until nothing changes, returning false if a variable is left with no values.
+// A variable with one value left takes it from the others, and a value only one variable can take is given to it.
+func (p *Permutation) Propagate() bool {
+	for changed := true; changed; {
+		changed = false
+
+		for v, domain := range p.domains {
+			switch domain.Len() {
+			case 0:
+				return false
+			case 1:
+				for other := range p.domains {
+					if other != v && p.domains[other]&domain != 0 {
+						p.domains[other] &^= domain
+						changed = true
+					}
+				}
+			}
+		}
+
+		for value := range p.domains {
+			only := -1
+			for v, domain := range p.domains {
+				if !domain.Has(value) {
+					continue
+				} else if only != -1 {
+					only = -2
+					break
+				}
+				only = v
+			}
+			switch {
+			case only == -1:
+				// Nothing can take the value any more
+				return false
+			case only >= 0 && p.domains[only].Len() > 1:
+				p.domains[only] = SetOf(value)
+				changed = true
+			}
+		}
+	}
+	return true
+}
+
+// Solve returns the first mapping of each variable to its value that valid accepts,
+// propagating after every choice so only mappings the domains allow are tried
+func (p *Permutation) Solve(valid func(mapping []int) bool) ([]int, bool) {
+	domains := append([]Set(nil), p.domains...)
+	defer func() { p.domains = domains }()
+
+	if !p.Propagate() {
+		return nil, false
+	}
+
+	// Branch on the most constrained variable that's still open
+	branch := -1
+	for v, domain := range p.domains {
+		if domain.Len() > 1 && (branch == -1 || domain.Len() < p.domains[branch].Len()) {
+			branch = v
+		}
+	}
+
+	if branch == -1 {
+		mapping := make([]int, len(p.domains))
+		for v, domain := range p.domains {
+			mapping[v] = domain.Values()[0]
+		}
+		return mapping, valid(mapping)
+	}
+
+	for _, value := range p.domains[branch].Values() {
+		saved := append([]Set(nil), p.domains...)
+		p.domains[branch] = SetOf(value)
+		if mapping, ok := p.Solve(valid); ok {
+			return mapping, true
+		}
+		p.domains = saved
+	}
+	return nil, false
+}
@@ -0,0 +1,156 @@
+package constraint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := SetOf(0, 3, 5)
+	if !s.Has(3) || s.Has(4) || s.Len() != 3 {
+		t.Errorf("SetOf(0, 3, 5) = %b", s)
+	}
+	if got := s.Values(); !reflect.DeepEqual(got, []int{0, 3, 5}) {
+		t.Errorf("Values = %v, want [0 3 5]", got)
+	}
+	if got := Full(4); got != SetOf(0, 1, 2, 3) {
+		t.Errorf("Full(4) = %b", got)
+	}
+	if got := Full(64); got.Len() != 64 {
+		t.Errorf("Full(64) has %d values", got.Len())
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	tests := []struct {
+		name     string
+		restrict []Set
+		ok       bool
+		want     []Set
+	}{
+		{
+			name:     "a singleton is removed from the others",
+			restrict: []Set{SetOf(1), Full(3), Full(3)},
+			ok:       true,
+			want:     []Set{SetOf(1), SetOf(0, 2), SetOf(0, 2)},
+		},
+		{
+			name:     "singletons chain",
+			restrict: []Set{SetOf(0), SetOf(0, 1), Full(3)},
+			ok:       true,
+			want:     []Set{SetOf(0), SetOf(1), SetOf(2)},
+		},
+		{
+			name:     "a value only one variable can take is assigned to it",
+			restrict: []Set{SetOf(0, 1), SetOf(0, 1), Full(3)},
+			ok:       true,
+			want:     []Set{SetOf(0, 1), SetOf(0, 1), SetOf(2)},
+		},
+		{
+			name:     "an empty domain fails",
+			restrict: []Set{0, Full(3), Full(3)},
+			ok:       false,
+		},
+		{
+			name:     "singletons emptying a domain fail",
+			restrict: []Set{SetOf(0), SetOf(1), SetOf(0, 1)},
+			ok:       false,
+		},
+		{
+			name:     "a value nothing can take fails",
+			restrict: []Set{SetOf(0, 1), SetOf(0, 1), SetOf(0, 1)},
+			ok:       false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewPermutation(len(test.restrict))
+			for v, allowed := range test.restrict {
+				p.Restrict(v, allowed)
+			}
+			if ok := p.Propagate(); ok != test.ok {
+				t.Fatalf("Propagate = %v, want %v", ok, test.ok)
+			}
+			if !test.ok {
+				return
+			}
+			for v, want := range test.want {
+				if got := p.Domain(v); got != want {
+					t.Errorf("domain of %d = %v, want %v", v, got.Values(), want.Values())
+				}
+			}
+		})
+	}
+}
+
+// isPermutation checks that mapping uses each value from 0 to len(mapping)-1 once
+func isPermutation(mapping []int) bool {
+	var seen Set
+	for _, value := range mapping {
+		if value < 0 || value >= len(mapping) || seen.Has(value) {
+			return false
+		}
+		seen |= SetOf(value)
+	}
+	return true
+}
+
+func TestSolve(t *testing.T) {
+	p := NewPermutation(5)
+	p.Restrict(0, SetOf(3, 4))
+	p.Restrict(2, SetOf(1, 2))
+
+	// Only accept the mapping reversing the variables
+	want := []int{4, 3, 2, 1, 0}
+	mapping, ok := p.Solve(func(mapping []int) bool {
+		if !isPermutation(mapping) {
+			t.Errorf("tried %v, which isn't a permutation", mapping)
+		}
+		return reflect.DeepEqual(mapping, want)
+	})
+	if !ok || !reflect.DeepEqual(mapping, want) {
+		t.Errorf("Solve = %v, %v, want %v", mapping, ok, want)
+	}
+
+	// Solving leaves the domains as they were
+	if got := p.Domain(0); got != SetOf(3, 4) {
+		t.Errorf("domain of 0 = %v after solving", got.Values())
+	}
+	if got := p.Domain(1); got != Full(5) {
+		t.Errorf("domain of 1 = %v after solving", got.Values())
+	}
+}
+
+func TestSolveFirst(t *testing.T) {
+	p := NewPermutation(6)
+	mapping, ok := p.Solve(func([]int) bool { return true })
+	if !ok || !isPermutation(mapping) {
+		t.Errorf("Solve = %v, %v, want a permutation", mapping, ok)
+	}
+}
+
+func TestSolveRejected(t *testing.T) {
+	tried := 0
+	p := NewPermutation(4)
+	mapping, ok := p.Solve(func([]int) bool {
+		tried++
+		return false
+	})
+	if ok || mapping != nil {
+		t.Errorf("Solve = %v, %v, want no mapping", mapping, ok)
+	}
+	// Every permutation was tried once
+	if tried != 24 {
+		t.Errorf("tried %d mappings, want 24", tried)
+	}
+}
+
+func TestSolveImpossible(t *testing.T) {
+	p := NewPermutation(3)
+	p.Restrict(0, SetOf(0))
+	p.Restrict(1, SetOf(0))
+	if _, ok := p.Solve(func([]int) bool { return true }); ok {
+		t.Error("solved two variables that can only take the same value")
+	}
+}
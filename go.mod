@@ -1,10 +1,10 @@
 module aoc
 
-go 1.17
-
-require github.com/PuerkitoBio/goquery v1.8.0
+go 1.18
 
 require (
-	github.com/andybalholm/cascadia v1.3.1 // indirect
-	golang.org/x/net v0.0.0-20210916014120-12bc252f5db8 // indirect
+	github.com/PuerkitoBio/goquery v1.8.0
+	golang.org/x/net v0.0.0-20210916014120-12bc252f5db8
 )
+
+require github.com/andybalholm/cascadia v1.3.1 // indirect
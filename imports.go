@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+const (
+	yearImportsName = "days.go"
+	rootImportsName = "years.go"
+	generatedHeader = "// Code generated by aoc init. DO NOT EDIT.\n\n"
+)
+
+// numberedDirs returns the subdirectories of dir named with a number, in numeric order
+func numberedDirs(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var nums []int
+	for _, entry := range entries {
+		if n, err := strconv.Atoi(entry.Name()); err == nil && entry.IsDir() {
+			nums = append(nums, n)
+		}
+	}
+	sort.Ints(nums)
+	return nums, nil
+}
+
+// hasGo checks if a directory has Go files, since some days are solved in other languages
+func hasGo(dir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	return len(matches) > 0
+}
+
+// updateImports regenerates the files importing every day, so they all register with the runner
+func updateImports() error {
+	years, err := numberedDirs(".")
+	if err != nil {
+		return err
+	}
+
+	var imported []int
+	for _, year := range years {
+		days, err := numberedDirs(strconv.Itoa(year))
+		if err != nil {
+			return err
+		}
+
+		var paths []string
+		for _, day := range days {
+			if hasGo(fmt.Sprintf("%d/%d", year, day)) {
+				paths = append(paths, fmt.Sprintf("aoc/%d/%d", year, day))
+			}
+		}
+		if len(paths) == 0 {
+			continue
+		}
+
+		doc := fmt.Sprintf("// Package year%d imports every day of %d so they register themselves.\n", year, year)
+		err = writeImports(fmt.Sprintf("%d/%s", year, yearImportsName), doc, fmt.Sprintf("year%d", year), paths)
+		if err != nil {
+			return err
+		}
+		imported = append(imported, year)
+	}
+
+	paths := make([]string, 0, len(imported))
+	for _, year := range imported {
+		paths = append(paths, fmt.Sprintf("aoc/%d", year))
+	}
+	return writeImports(rootImportsName, "", "main", paths)
+}
+
+func writeImports(name, doc, pkg string, paths []string) error {
+	b := &bytes.Buffer{}
+	b.WriteString(generatedHeader)
+	b.WriteString(doc)
+	fmt.Fprintf(b, "package %s\n\nimport (\n", pkg)
+	for _, path := range paths {
+		fmt.Fprintf(b, "\t_ %q\n", path)
+	}
+	b.WriteString(")\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, src, 0644)
+}
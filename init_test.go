@@ -0,0 +1,295 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aoc/aoctest"
+	"aoc/client"
+)
+
+// newSite starts a fake site with two puzzles and a client logged in to it
+func newSite(t *testing.T) (*aoctest.Server, *client.Client) {
+	t.Helper()
+	client.RequestInterval = 0
+
+	s := aoctest.NewServer()
+	t.Cleanup(s.Close)
+	s.Session = "session"
+	for day, input := range []string{"199\n200\n208\n", "forward 5\ndown 5\n"} {
+		s.AddPuzzle(2030, day+1, aoctest.Puzzle{
+			Title:   "Test",
+			Parts:   [2]string{"<p>For example:</p><pre><code>1\n2\n</code></pre><p>That gives <code><em>3</em></code>.</p>", ""},
+			Input:   input,
+			Answers: [2]string{"3", "4"},
+		})
+	}
+
+	c, err := client.NewWithSession(s.URL, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CacheDir = t.TempDir()
+	return s, c
+}
+
+// inTempDir runs the rest of the test in an empty directory with the templates
+func inTempDir(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Symlink(filepath.Join(wd, templateDir), filepath.Join(dir, templateDir)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMakeClientSessionFlag(t *testing.T) {
+	s, _ := newSite(t)
+	s.Session = "flag"
+	t.Setenv(client.SessionEnv, "env")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	// The flag beats the environment, or the site would refuse the session and end the test
+	c := makeClient(&options{url: s.URL, session: "flag"})
+	if err := c.CheckSession(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetDays(t *testing.T) {
+	_, c := newSite(t)
+	if got, want := getDays(c, "2030"), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getDays = %v, want %v", got, want)
+	}
+}
+
+func TestFindTemplate(t *testing.T) {
+	for _, name := range templateNames() {
+		src, dst, p, err := findTemplate(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		// Every Go template is the skeleton with a parser
+		if _, ok := parsers[name]; ok != (dst == "main.go") || ok != (p.Func != "") || ok != strings.HasSuffix(src, "go.go.tmpl") {
+			t.Errorf("%s = %s, %s, %+v", name, src, dst, p)
+		}
+	}
+
+	for _, name := range []string{goTemplate, autoTemplate, "missing"} {
+		if _, _, _, err := findTemplate(name); err == nil {
+			t.Errorf("found a template for %q", name)
+		}
+	}
+}
+
+func TestDownloadInput(t *testing.T) {
+	_, c := newSite(t)
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "input.txt")
+	if err := downloadInput(c, "2030", "1", name); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, name); got != "199\n200\n208\n" {
+		t.Errorf("input = %q", got)
+	}
+
+	// Failed downloads leave nothing behind
+	missing := filepath.Join(dir, "missing.txt")
+	var statusErr *statusError
+	if err := downloadInput(c, "2030", "9", missing); !errors.As(err, &statusErr) || statusErr.code != http.StatusNotFound {
+		t.Errorf("error = %v, want a 404", err)
+	}
+	if _, err := os.Stat(missing); err == nil {
+		t.Error("a missing input was written")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("%d files in the directory, want only input.txt", len(entries))
+	}
+}
+
+func TestDownloadInputLoggedOut(t *testing.T) {
+	s, _ := newSite(t)
+	c, err := client.NewWithSession(s.URL, "expired")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CacheDir = t.TempDir()
+
+	name := filepath.Join(t.TempDir(), "input.txt")
+	if err := downloadInput(c, "2030", "1", name); !errors.Is(err, client.ErrLoggedOut) {
+		t.Errorf("error = %v, want ErrLoggedOut", err)
+	}
+	if _, err := os.Stat(name); err == nil {
+		t.Error("the error page was written to the input")
+	}
+}
+
+func TestInitializeDay(t *testing.T) {
+	_, c := newSite(t)
+	inTempDir(t)
+
+	skipped, err := initializeDay(c, "2030", "1", false, autoTemplate)
+	if err != nil || skipped {
+		t.Fatalf("initializeDay = %v, %v", skipped, err)
+	}
+	for name, want := range map[string]string{
+		"input.txt":           "199\n200\n208\n",
+		"example.txt":         "1\n2\n",
+		"example_answers.txt": "3\n",
+	} {
+		if got := readFile(t, "2030/1/"+name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if got := readFile(t, "2030/1/README.md"); !strings.HasPrefix(got, "## --- Day 1: Test ---") {
+		t.Errorf("README.md starts with %q", strings.SplitN(got, "\n", 2)[0])
+	}
+	// The numbers in the input pick the ints template
+	main := readFile(t, "2030/1/main.go")
+	for _, want := range []string{"package day1", "day 1: Test", "input.Ints(scanner)", "registry.Register(2030, 1,"} {
+		if !strings.Contains(main, want) {
+			t.Errorf("main.go is missing %q", want)
+		}
+	}
+	if got := readFile(t, "2030/1/main_test.go"); !strings.HasPrefix(got, "package day1\n") {
+		t.Errorf("main_test.go starts with %q", strings.SplitN(got, "\n", 2)[0])
+	}
+
+	// Everything is there, so nothing happens
+	if skipped, err := initializeDay(c, "2030", "1", false, autoTemplate); err != nil || !skipped {
+		t.Errorf("second initializeDay = %v, %v, want skipped", skipped, err)
+	}
+}
+
+func TestInitializeDayKeepsSolution(t *testing.T) {
+	s, c := newSite(t)
+	inTempDir(t)
+
+	if _, err := initializeDay(c, "2030", "1", false, autoTemplate); err != nil {
+		t.Fatal(err)
+	}
+	solution := "package day1\n\n// solved\n"
+	if err := os.WriteFile("2030/1/main.go", []byte(solution), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fetching a deleted input again leaves the solution alone
+	if err := os.Remove("2030/1/input.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if skipped, err := initializeDay(c, "2030", "1", false, autoTemplate); err != nil || skipped {
+		t.Fatalf("initializeDay = %v, %v", skipped, err)
+	}
+	if got := readFile(t, "2030/1/main.go"); got != solution {
+		t.Errorf("main.go was replaced with %q", got)
+	}
+	if _, err := os.Stat("2030/1/input.txt"); err != nil {
+		t.Error(err)
+	}
+
+	// Until forced, which also downloads the input again rather than using the cache
+	downloads := s.Requests("/2030/day/1/input")
+	if _, err := initializeDay(c, "2030", "1", true, autoTemplate); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, "2030/1/main.go"); got == solution {
+		t.Error("main.go wasn't replaced when forced")
+	}
+	if s.Requests("/2030/day/1/input") != downloads+1 {
+		t.Error("the forced input came from the cache")
+	}
+}
+
+func TestSavePuzzlePartTwo(t *testing.T) {
+	s, c := newSite(t)
+	dir := t.TempDir()
+
+	if err := savePuzzle(c, "2030", "1", dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "example.txt"), []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Solving part one unlocks part two, which is added to what was saved
+	s.SetSolved(2030, 1, 1)
+	c.Forget(s.URL + "/2030/day/1")
+	if err := savePuzzle(c, "2030", "1", dir); err != nil {
+		t.Fatal(err)
+	}
+	readme := readFile(t, filepath.Join(dir, "README.md"))
+	if strings.Count(readme, "## --- Day 1: Test ---") != 1 || !strings.Contains(readme, "## --- Part Two ---") {
+		t.Errorf("README.md doesn't have each part once:\n%s", readme)
+	}
+	if got := readFile(t, filepath.Join(dir, "example_answers.txt")); got != "3\n\n" {
+		t.Errorf("example_answers.txt = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "example.txt")); got != "edited\n" {
+		t.Errorf("the edited example was replaced with %q", got)
+	}
+}
+
+func TestInitializeDaySwitchesLanguage(t *testing.T) {
+	_, c := newSite(t)
+	inTempDir(t)
+
+	if _, err := initializeDay(c, "2030", "1", false, autoTemplate); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another language sits next to the Go solution until forced
+	if _, err := initializeDay(c, "2030", "1", false, "python"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("2030/1/main.py"); err == nil {
+		t.Error("main.py was written without --force")
+	}
+
+	if _, err := initializeDay(c, "2030", "1", true, "python"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, "2030/1/main.py"); !strings.Contains(got, "day 1") {
+		t.Errorf("main.py = %q", got)
+	}
+	for _, name := range []string{"main.go", testName} {
+		if _, err := os.Stat("2030/1/" + name); err == nil {
+			t.Errorf("%s is still there next to main.py", name)
+		}
+	}
+}
+
+func TestInitializeDays(t *testing.T) {
+	_, c := newSite(t)
+	inTempDir(t)
+
+	// Day 3 doesn't exist, but doesn't stop the others
+	if failed := initializeDays(c, "2030", []string{"1", "2", "3"}, false, autoTemplate, 2); failed != 1 {
+		t.Errorf("%d days failed, want 1", failed)
+	}
+	if main := readFile(t, "2030/2/main.go"); !strings.Contains(main, "input.Commands(scanner)") {
+		t.Error("day 2 doesn't parse commands")
+	}
+}
@@ -0,0 +1,183 @@
+// Package input parses puzzle inputs.
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Lines returns every line of the input
+func Lines(scanner *bufio.Scanner) (output []string, err error) {
+	// Scan the input text
+	for scanner.Scan() {
+		// Use the text in the output
+		output = append(output, scanner.Text())
+	}
+	return output, scanner.Err()
+}
+
+// Ints returns the number on each line of the input
+func Ints(scanner *bufio.Scanner) (output []int, err error) {
+	lines, err := Lines(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	output = make([]int, 0, len(lines))
+	for _, line := range lines {
+		// Convert to number
+		n, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, n)
+	}
+	return output, nil
+}
+
+// CommaInts returns the comma separated numbers on every line of the input
+func CommaInts(scanner *bufio.Scanner) (output []int, err error) {
+	lines, err := Lines(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		for _, p := range strings.Split(line, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				return nil, err
+			}
+			output = append(output, n)
+		}
+	}
+	return output, nil
+}
+
+// Digits returns each line of the input as a row of single digit numbers
+func Digits(scanner *bufio.Scanner) (output [][]int, err error) {
+	lines, err := Lines(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	output = make([][]int, 0, len(lines))
+	for _, line := range lines {
+		row := make([]int, len(line))
+		for i, c := range line {
+			if c < '0' || c > '9' {
+				return nil, &strconv.NumError{Func: "Digits", Num: string(c), Err: strconv.ErrSyntax}
+			}
+			row[i] = int(c - '0')
+		}
+		output = append(output, row)
+	}
+	return output, nil
+}
+
+// Blocks returns the groups of lines separated by blank lines in the input
+func Blocks(scanner *bufio.Scanner) (output [][]string, err error) {
+	lines, err := Lines(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	var block []string
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			// Several blank lines in a row don't make empty blocks
+			if len(block) > 0 {
+				output = append(output, block)
+				block = nil
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		output = append(output, block)
+	}
+	return output, nil
+}
+
+// Command is a line made of a word and a number, such as "forward 5"
+type Command struct {
+	Word string
+	N    int
+}
+
+// Commands returns the word and number on each line of the input
+func Commands(scanner *bufio.Scanner) (output []Command, err error) {
+	lines, err := Lines(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	output = make([]Command, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%q is not a word and a number", line)
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, Command{Word: fields[0], N: n})
+	}
+	return output, nil
+}
+
+// Bits returns each line of the input as a row of bits, true for a 1 and false for a 0
+func Bits(scanner *bufio.Scanner) (output [][]bool, err error) {
+	lines, err := Lines(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	output = make([][]bool, 0, len(lines))
+	for _, line := range lines {
+		row := make([]bool, len(line))
+		for i, c := range line {
+			if c != '0' && c != '1' {
+				return nil, &strconv.NumError{Func: "Bits", Num: string(c), Err: strconv.ErrSyntax}
+			}
+			row[i] = c == '1'
+		}
+		output = append(output, row)
+	}
+	return output, nil
+}
+
+// Point is a position on a grid
+type Point struct {
+	X, Y int
+}
+
+// Segment is a line between two points, written "x1,y1 -> x2,y2"
+type Segment struct {
+	From, To Point
+}
+
+// Segments returns the segment on each line of the input
+func Segments(scanner *bufio.Scanner) (output []Segment, err error) {
+	lines, err := Lines(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	output = make([]Segment, 0, len(lines))
+	for _, line := range lines {
+		var s Segment
+		if _, err := fmt.Sscanf(line, "%d,%d -> %d,%d", &s.From.X, &s.From.Y, &s.To.X, &s.To.Y); err != nil {
+			return nil, fmt.Errorf("%q is not a segment: %w", line, err)
+		}
+		output = append(output, s)
+	}
+	return output, nil
+}
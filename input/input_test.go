@@ -0,0 +1,103 @@
+package input
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func scan(text string) *bufio.Scanner {
+	return bufio.NewScanner(strings.NewReader(text))
+}
+
+func TestLines(t *testing.T) {
+	got, err := Lines(scan("a b\n\nc\n"))
+	if want := []string{"a b", "", "c"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestInts(t *testing.T) {
+	got, err := Ints(scan("199\n -200 \n0\n"))
+	if want := []int{199, -200, 0}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Ints = %v, %v, want %v", got, err, want)
+	}
+	for _, bad := range []string{"1\nx\n", "1\n\n2\n", "1.5\n"} {
+		if _, err := Ints(scan(bad)); err == nil {
+			t.Errorf("Ints(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestCommaInts(t *testing.T) {
+	got, err := CommaInts(scan("3,4, -3\n\n1,2\n"))
+	if want := []int{3, 4, -3, 1, 2}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("CommaInts = %v, %v, want %v", got, err, want)
+	}
+	for _, bad := range []string{"1,,2\n", "1,a\n", "1 2\n"} {
+		if _, err := CommaInts(scan(bad)); err == nil {
+			t.Errorf("CommaInts(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestDigits(t *testing.T) {
+	got, err := Digits(scan("219\n398\n"))
+	if want := [][]int{{2, 1, 9}, {3, 9, 8}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Digits = %v, %v, want %v", got, err, want)
+	}
+	for _, bad := range []string{"12\n3a\n", "-1\n", "1 2\n"} {
+		if _, err := Digits(scan(bad)); err == nil {
+			t.Errorf("Digits(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	got, err := Blocks(scan("\n7,4,9\n\n22 13\n 8  2\n\n\n3 15\n"))
+	want := [][]string{{"7,4,9"}, {"22 13", " 8  2"}, {"3 15"}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Blocks = %q, %v, want %q", got, err, want)
+	}
+	if got, err := Blocks(scan("\n\n")); err != nil || len(got) != 0 {
+		t.Errorf("Blocks of blank lines = %q, %v, want none", got, err)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	got, err := Commands(scan("forward 5\ndown  -3\n"))
+	if want := []Command{{"forward", 5}, {"down", -3}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Commands = %v, %v, want %v", got, err, want)
+	}
+	for _, bad := range []string{"forward\n", "forward 5 6\n", "forward five\n", "\n"} {
+		if _, err := Commands(scan(bad)); err == nil {
+			t.Errorf("Commands(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestBits(t *testing.T) {
+	got, err := Bits(scan("001\n110\n"))
+	if want := [][]bool{{false, false, true}, {true, true, false}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Bits = %v, %v, want %v", got, err, want)
+	}
+	for _, bad := range []string{"012\n", "1 0\n", "x\n"} {
+		if _, err := Bits(scan(bad)); err == nil {
+			t.Errorf("Bits(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	got, err := Segments(scan("0,9 -> 5,9\n8,0 -> 0,8\n"))
+	want := []Segment{{Point{0, 9}, Point{5, 9}}, {Point{8, 0}, Point{0, 8}}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Segments = %v, %v, want %v", got, err, want)
+	}
+	for _, bad := range []string{"0,9 5,9\n", "0,9 -> 5\n", "a,b -> c,d\n", "\n"} {
+		if _, err := Segments(scan(bad)); err == nil {
+			t.Errorf("Segments(%q) succeeded", bad)
+		}
+	}
+}
@@ -4,82 +4,47 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
-	"net/http"
-	"net/http/cookiejar"
-	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
+
+	"aoc/client"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
-var URL = "https://adventofcode.com"
-
-func getAuth() *http.Cookie {
-	data, err := ioutil.ReadFile("auth.txt")
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	return &http.Cookie{
-		Name:     "session",
-		Value:    string(data),
-		Path:     "/",
-		Domain:   ".adventofcode.com",
-		Secure:   true,
-		HttpOnly: true,
+func makeClient(opts *options) *client.Client {
+	// A session given as a flag beats the ones client.Session finds
+	newClient := client.NewWithURL
+	if opts.session != "" {
+		newClient = func(base string) (*client.Client, error) {
+			return client.NewWithSession(base, opts.session)
+		}
 	}
-}
 
-func makeClient() *http.Client {
-	jar, err := cookiejar.New(nil)
+	c, err := newClient(opts.url)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	urlObj, _ := url.Parse(URL)
-	jar.SetCookies(urlObj, []*http.Cookie{getAuth()})
-
-	return &http.Client{
-		Transport:     nil,
-		CheckRedirect: nil,
-		Jar:           jar,
-		Timeout:       0,
+	c.UserAgent = opts.userAgent
+	c.Refresh = opts.refresh
+	if opts.userAgent == client.UserAgent() && os.Getenv(client.UserAgentEnv) == "" {
+		log.Println("Warning: set " + client.UserAgentEnv + " or --user-agent to your contact details, such as an email address, so the site can reach you")
 	}
-}
 
-func getDocument(client *http.Client, url string) (*goquery.Document, error) {
-	// Get the HTML
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	} else if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, errors.New(resp.Status)
-	}
-
-	// Convert HTML into goquery document
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return doc, err
-	}
-	return doc, nil
-}
-func getYear() (year string) {
-	if len(os.Args) < 2 {
-		// Get current year
-		year = strconv.Itoa(time.Now().Year())
-	} else {
-		// Assume this is a valid year
-		year = os.Args[1]
+	// Find out about an expired session before doing anything else
+	if err := c.CheckSession(); err != nil {
+		log.Fatalln(err)
 	}
-	return year
+	return c
 }
 
-func getDays(client *http.Client, year string) []string {
-	doc, err := getDocument(client, URL+"/"+year)
+func getDays(client *client.Client, year string) []string {
+	doc, err := client.Document(client.URL + "/" + year)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -93,84 +58,321 @@ func getDays(client *http.Client, year string) []string {
 	return days
 }
 
-func initializeDays(client *http.Client, year string, days []string) {
-	for _, day := range days {
-		inputName := fmt.Sprintf("%s/%s/input.txt", year, day)
-		goName := fmt.Sprintf("%s/%s/main.go", year, day)
-		// See if the file exists
-		if _, err := os.Stat(inputName); err == nil {
-			continue
+// dayOutcome is how setting up one day went
+type dayOutcome struct {
+	day     string
+	skipped bool
+	err     error
+}
+
+// initializeDays sets up the days with a pool of workers, which share the client's rate limit,
+// and returns how many days failed
+func initializeDays(c *client.Client, year string, days []string, force bool, templateName string, workers int) int {
+	jobs := make(chan string)
+	outcomes := make(chan dayOutcome)
+
+	// Once the session is refused every other day would fail the same way
+	var mu sync.Mutex
+	loggedOut := false
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for day := range jobs {
+				mu.Lock()
+				stop := loggedOut
+				mu.Unlock()
+				if stop {
+					outcomes <- dayOutcome{day: day, err: client.ErrLoggedOut}
+					continue
+				}
+
+				skipped, err := initializeDay(c, year, day, force, templateName)
+				if errors.Is(err, client.ErrLoggedOut) {
+					mu.Lock()
+					loggedOut = true
+					mu.Unlock()
+				}
+				outcomes <- dayOutcome{day: day, skipped: skipped, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, day := range days {
+			jobs <- day
 		}
+		close(jobs)
+		wg.Wait()
+		close(outcomes)
+	}()
 
-		// Get the input for the day
-		urlStr := fmt.Sprintf("%s/%s/day/%s/input", URL, year, day)
-		resp, err := client.Get(urlStr)
-		if err != nil {
-			log.Fatalln(err)
+	var done, skipped, failed []dayOutcome
+	for outcome := range outcomes {
+		switch {
+		case outcome.err != nil:
+			failed = append(failed, outcome)
+		case outcome.skipped:
+			skipped = append(skipped, outcome)
+		default:
+			done = append(done, outcome)
 		}
+	}
 
-		if resp.StatusCode != 200 {
-			log.Println(urlStr, resp.Status)
-			continue
+	log.Printf("Set up %d %s, skipped %d and %d failed\n", len(done), plural(len(done), "day"), len(skipped), len(failed))
+	printOutcomes("Set up", done)
+	printOutcomes("Skipped", skipped)
+	sortOutcomes(failed)
+	for _, outcome := range failed {
+		log.Printf("Failed %s/%s: %v\n", year, outcome.day, outcome.err)
+	}
+	return len(failed)
+}
+
+func sortOutcomes(outcomes []dayOutcome) {
+	sort.Slice(outcomes, func(i, j int) bool {
+		a, _ := strconv.Atoi(outcomes[i].day)
+		b, _ := strconv.Atoi(outcomes[j].day)
+		return a < b
+	})
+}
+
+func printOutcomes(label string, outcomes []dayOutcome) {
+	if len(outcomes) == 0 {
+		return
+	}
+	sortOutcomes(outcomes)
+	days := make([]string, len(outcomes))
+	for i, outcome := range outcomes {
+		days[i] = outcome.day
+	}
+	log.Println(label+":", strings.Join(days, " "))
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// initializeDay fills in whatever a day is missing, only replacing the input and code when forced.
+// The solution is copied from the named template, or one picked from the input's shape if it's autoTemplate.
+func initializeDay(c *client.Client, year, day string, force bool, templateName string) (skipped bool, err error) {
+	dir := fmt.Sprintf("%s/%s", year, day)
+	inputName := dir + "/input.txt"
+
+	// Create the directory for the day if it doesn't exist
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	skipped = true
+
+	// Get the input for the day, going back to the site rather than the cache when forced
+	if force || !exists(inputName) {
+		if force {
+			c.Forget(fmt.Sprintf("%s/%s/day/%s/input", c.URL, year, day))
+			c.Forget(fmt.Sprintf("%s/%s/day/%s", c.URL, year, day))
+		}
+		if err := downloadInput(c, year, day, inputName); err != nil {
+			return false, err
 		}
+		skipped = false
 
-		outFile, err := os.Create(inputName)
+		// Save the puzzle description and example next to the input
+		if err := savePuzzle(c, year, day, dir); errors.Is(err, client.ErrLoggedOut) {
+			return false, err
+		} else if err != nil {
+			log.Println(err)
+		}
+	}
+
+	if templateName == autoTemplate {
+		data, err := os.ReadFile(inputName)
 		if err != nil {
-			log.Fatalln(err)
+			return false, err
+		}
+		templateName = analyzeInput(data).parser
+	}
+	src, mainName, p, err := findTemplate(templateName)
+	if err != nil {
+		return false, err
+	}
+	mainName = dir + "/" + mainName
+
+	// Copy the template into the new directory along with a test of the example,
+	// unless the day already has a solution in any language
+	if force || !hasSolution(dir) {
+		if err := copyTemplate(mainName, src, year, day, p, force); err != nil {
+			return false, err
+		}
+		skipped = false
+		// A forced template in another language replaces the old solution rather than sitting next to it
+		if err := removeOtherSolutions(dir, mainName); err != nil {
+			return false, err
+		}
+		if !strings.HasSuffix(mainName, ".go") || exists(dir+"/"+testName) && !force {
+			return skipped, nil
+		}
+		if err := copyTemplate(dir+"/"+testName, testTemplate, year, day, parser{}, force); err != nil {
+			return false, err
 		}
+	} else if !skipped {
+		log.Println("Kept the existing solution in", dir+", use --force to replace it with the template")
+	}
+	return skipped, nil
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
 
-		io.Copy(outFile, resp.Body)
-		outFile.Close()
+// hasSolution checks for a main file in any language, such as main.go or main.py
+func hasSolution(dir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "main.*"))
+	return len(matches) > 0
+}
 
-		// Copy the template.go into the new directory
-		copyFile(goName, "templates/template.go")
+// removeOtherSolutions removes every main file in dir but keep, along with the Go test if keep isn't Go
+func removeOtherSolutions(dir, keep string) error {
+	matches, _ := filepath.Glob(filepath.Join(dir, "main.*"))
+	if !strings.HasSuffix(keep, ".go") && exists(filepath.Join(dir, testName)) {
+		matches = append(matches, filepath.Join(dir, testName))
 	}
+	for _, match := range matches {
+		if filepath.Base(match) == filepath.Base(keep) {
+			continue
+		}
+		if err := os.Remove(match); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func copyFile(dst, src string) {
-	// Read the source file
-	srcFile, err := os.Open(src)
-	if err != nil {
-		log.Fatalln(err)
+// otherSolution returns the main file of a day solved in a language other than Go, such as main.py
+func otherSolution(dir string) string {
+	if hasGo(dir) {
+		return ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "main.*"))
+	if len(matches) == 0 {
+		return ""
 	}
-	defer srcFile.Close()
+	return filepath.Base(matches[0])
+}
 
-	// Create the file if it doesn't exist
-	dstFile, err := os.Create(dst)
+const (
+	testName     = "main_test.go"
+	testTemplate = "templates/main_test.go.tmpl"
+)
+
+// addTests writes the example test for each day of the year written in Go that doesn't have one
+func addTests(year string) error {
+	days, err := numberedDirs(year)
 	if err != nil {
-		log.Fatalln(err)
+		return err
+	}
+
+	for _, day := range days {
+		dir := fmt.Sprintf("%s/%d", year, day)
+		if !hasGo(dir) {
+			continue
+		}
+		if exists(dir + "/" + testName) {
+			continue
+		}
+		if err := copyTemplate(dir+"/"+testName, testTemplate, year, strconv.Itoa(day), parser{}, false); err != nil {
+			return err
+		}
+		log.Println("Added", dir+"/"+testName)
 	}
-	defer dstFile.Close()
+	return nil
+}
+
+// statusError is returned when the server doesn't answer with a 200
+type statusError struct {
+	url    string
+	code   int
+	status string
+}
 
-	// Copy the data over
-	_, err = io.Copy(dstFile, srcFile)
+func (e *statusError) Error() string {
+	return e.url + " " + e.status
+}
+
+func downloadInput(client *client.Client, year, day, inputName string) error {
+	urlStr := fmt.Sprintf("%s/%s/day/%s/input", client.URL, year, day)
+	resp, err := client.Get(urlStr)
 	if err != nil {
-		log.Fatalln(err)
+		return err
 	}
+	defer resp.Body.Close()
 
-	// Sync the file contents to the disk
-	err = dstFile.Sync()
+	if resp.StatusCode != 200 {
+		return &statusError{url: urlStr, code: resp.StatusCode, status: resp.Status}
+	}
+
+	// A failed download never leaves a partial input behind
+	return writeFile(inputName, true, func(w io.Writer) error {
+		_, err := io.Copy(w, resp.Body)
+		return err
+	})
+}
+
+// writeFile writes a file through a temporary file next to it, so it is either
+// written completely or not at all. Unless replace is set, an existing file is left
+// alone and the error is os.ErrExist.
+func writeFile(name string, replace bool, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+"-*")
 	if err != nil {
-		log.Fatalln(err)
+		return err
 	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	// Sync the file contents to the disk before it takes the place of the old one
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	if replace {
+		return os.Rename(tmp.Name(), name)
+	}
+	// Linking fails if the file exists, even if it was created since we last looked
+	return os.Link(tmp.Name(), name)
 }
 
 func main() {
-	client := makeClient()
-
-	// Get the year
-	year := getYear()
-	// Get the days of this year
-	days := getDays(client, year)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
 
-	// Create the directory for each day if it doesn't exist
-	for _, day := range days {
-		err := os.MkdirAll(year+"/"+day, 0755)
-		if err != nil {
-			log.Fatalln(err)
-		}
+	name, args := os.Args[1], os.Args[2:]
+	// Keep supporting the old `aoc YEAR` form as a shortcut for init
+	if _, err := strconv.Atoi(name); err == nil {
+		name, args = "init", append([]string{"--year", name}, args...)
 	}
 
-	// Get all inputs if they don't exist
-	initializeDays(client, year, days)
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+	cmd.run(args)
 }
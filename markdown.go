@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`")
+
+// saveDescription writes the puzzle description to readmeName when it has parts that aren't saved yet
+func saveDescription(doc *goquery.Document, base, readmeName string) error {
+	// Convert each part of the puzzle
+	var parts []string
+	doc.Find("article.day-desc").Each(func(i int, s *goquery.Selection) {
+		parts = append(parts, toMarkdown(s, base))
+	})
+	if len(parts) == 0 {
+		return fmt.Errorf("no puzzle description found for %s", readmeName)
+	}
+
+	// Each part starts with a heading, so count them to see what is already saved
+	saved := 0
+	if data, err := os.ReadFile(readmeName); err == nil {
+		saved = strings.Count(string(data), "\n## --- ")
+		if strings.HasPrefix(string(data), "## --- ") {
+			saved++
+		}
+	}
+	if saved >= len(parts) {
+		return nil
+	}
+
+	// Write the whole description again, so an interrupted write never leaves part of a part
+	return writeFile(readmeName, true, func(w io.Writer) error {
+		_, err := io.WriteString(w, strings.Join(parts, ""))
+		return err
+	})
+}
+
+// toMarkdown converts a puzzle article into Markdown with links resolved against base
+func toMarkdown(s *goquery.Selection, base string) string {
+	b := &strings.Builder{}
+	for _, n := range s.Nodes {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			writeBlock(b, c, base)
+		}
+	}
+	return b.String()
+}
+
+func writeBlock(b *strings.Builder, n *html.Node, base string) {
+	if n.Type != html.ElementNode {
+		// Stray text between blocks is only formatting whitespace
+		if strings.TrimSpace(n.Data) != "" {
+			writeInline(b, n, base)
+			b.WriteString("\n\n")
+		}
+		return
+	}
+
+	switch n.Data {
+	case "h2":
+		b.WriteString("## ")
+		writeChildren(b, n, base)
+		b.WriteString("\n\n")
+	case "pre":
+		// Code blocks are kept exactly as they are
+		text := nodeText(n)
+		b.WriteString("```\n")
+		b.WriteString(text)
+		if !strings.HasSuffix(text, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("```\n\n")
+	case "ul", "ol":
+		i := 1
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.Data != "li" {
+				continue
+			}
+			if n.Data == "ol" {
+				fmt.Fprintf(b, "%d. ", i)
+				i++
+			} else {
+				b.WriteString("- ")
+			}
+			writeChildren(b, c, base)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	default:
+		writeChildren(b, n, base)
+		b.WriteString("\n\n")
+	}
+}
+
+func writeChildren(b *strings.Builder, n *html.Node, base string) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeInline(b, c, base)
+	}
+}
+
+func writeInline(b *strings.Builder, n *html.Node, base string) {
+	if n.Type == html.TextNode {
+		b.WriteString(markdownEscaper.Replace(strings.ReplaceAll(n.Data, "\n", " ")))
+		return
+	} else if n.Type != html.ElementNode {
+		return
+	}
+
+	switch n.Data {
+	case "code":
+		// Markdown can't emphasize inside of code, so emphasize the whole span
+		code := "`" + nodeText(n) + "`"
+		if hasChild(n, "em") {
+			code = "**" + code + "**"
+		}
+		b.WriteString(code)
+	case "em", "i":
+		b.WriteString("*")
+		writeChildren(b, n, base)
+		b.WriteString("*")
+	case "strong", "b":
+		b.WriteString("**")
+		writeChildren(b, n, base)
+		b.WriteString("**")
+	case "a":
+		b.WriteString("[")
+		writeChildren(b, n, base)
+		b.WriteString("](" + absoluteURL(base, attr(n, "href")) + ")")
+	case "br":
+		b.WriteString("  \n")
+	default:
+		writeChildren(b, n, base)
+	}
+}
+
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	text := ""
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		text += nodeText(c)
+	}
+	return text
+}
+
+func hasChild(n *html.Node, tag string) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag || hasChild(c, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// absoluteURL resolves links on the puzzle page against the site
+func absoluteURL(baseURL, href string) string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
@@ -0,0 +1,144 @@
+// Package mathutil has the small numeric helpers used across the days.
+package mathutil
+
+import "sort"
+
+// Number is any type the helpers work on
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+func Abs[T Number](a T) T {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func Sum[T Number](args ...T) (total T) {
+	for _, v := range args {
+		total += v
+	}
+	return total
+}
+
+// Product returns all of the arguments multiplied together, or 1 if there are none
+func Product[T Number](args ...T) T {
+	total := T(1)
+	for _, v := range args {
+		total *= v
+	}
+	return total
+}
+
+// Max returns the largest argument, or 0 if there are none
+func Max[T Number](args ...T) T {
+	m, _ := MaxIndex(args...)
+	return m
+}
+
+// Min returns the smallest argument, or 0 if there are none
+func Min[T Number](args ...T) T {
+	m, _ := MinIndex(args...)
+	return m
+}
+
+// MaxIndex returns the largest argument and the index of its first occurrence, or 0 and -1 if there are none
+func MaxIndex[T Number](args ...T) (elem T, idx int) {
+	if len(args) == 0 {
+		return 0, -1
+	}
+	for i, v := range args {
+		if i == 0 || v > elem {
+			elem, idx = v, i
+		}
+	}
+	return elem, idx
+}
+
+// MinIndex returns the smallest argument and the index of its first occurrence, or 0 and -1 if there are none
+func MinIndex[T Number](args ...T) (elem T, idx int) {
+	if len(args) == 0 {
+		return 0, -1
+	}
+	for i, v := range args {
+		if i == 0 || v < elem {
+			elem, idx = v, i
+		}
+	}
+	return elem, idx
+}
+
+// ArgMax returns the index of the largest argument, or -1 if there are none
+func ArgMax[T Number](args ...T) int {
+	_, idx := MaxIndex(args...)
+	return idx
+}
+
+// ArgMin returns the index of the smallest argument, or -1 if there are none
+func ArgMin[T Number](args ...T) int {
+	_, idx := MinIndex(args...)
+	return idx
+}
+
+// Mean returns the average of the arguments, or 0 if there are none
+func Mean[T Number](args ...T) float64 {
+	if len(args) == 0 {
+		return 0
+	}
+	return float64(Sum(args...)) / float64(len(args))
+}
+
+// Median returns the middle value, averaging the middle two for an even length.
+// The arguments are left in the order they were given.
+func Median[T Number](args ...T) T {
+	if len(args) == 0 {
+		return 0
+	}
+
+	sorted := make([]T, len(args))
+	copy(sorted, args)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	middle := len(sorted) / 2
+
+	// Check if odd
+	if len(sorted)&1 == 1 {
+		return sorted[middle]
+	}
+
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+// Mode returns the most common arguments in increasing order, which is more than one when there is a tie
+func Mode[T Number](args ...T) (common []T) {
+	m := make(map[T]int)
+	// Count up the number of occurrences of each number
+	for _, v := range args {
+		m[v] += 1
+	}
+
+	count := 0
+	for k, v := range m {
+		if v > count {
+			// If it was bigger, start over with just that key
+			count = v
+			common = []T{k}
+		} else if v == count {
+			// If the counts are the same append the key
+			common = append(common, k)
+		}
+	}
+
+	sort.Slice(common, func(i, j int) bool {
+		return common[i] < common[j]
+	})
+	return common
+}
+
+// Sequence returns the sum of 1 through n, the nth triangular number
+func Sequence[T Number](n T) T {
+	return n * (n + 1) / 2
+}
@@ -0,0 +1,118 @@
+package mathutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+// numberCase is a list of arguments and what each helper should return for them
+type numberCase[T Number] struct {
+	args    []T
+	sum     T
+	product T
+	max     T
+	maxIdx  int
+	min     T
+	minIdx  int
+	median  T
+	mean    float64
+	mode    []T
+}
+
+func checkNumbers[T Number](t *testing.T, cases []numberCase[T]) {
+	t.Helper()
+	for _, c := range cases {
+		if got := Sum(c.args...); got != c.sum {
+			t.Errorf("Sum(%v) = %v, want %v", c.args, got, c.sum)
+		}
+		if got := Product(c.args...); got != c.product {
+			t.Errorf("Product(%v) = %v, want %v", c.args, got, c.product)
+		}
+		if got := Max(c.args...); got != c.max {
+			t.Errorf("Max(%v) = %v, want %v", c.args, got, c.max)
+		}
+		if got, idx := MaxIndex(c.args...); got != c.max || idx != c.maxIdx {
+			t.Errorf("MaxIndex(%v) = %v, %d, want %v, %d", c.args, got, idx, c.max, c.maxIdx)
+		}
+		if got := ArgMax(c.args...); got != c.maxIdx {
+			t.Errorf("ArgMax(%v) = %d, want %d", c.args, got, c.maxIdx)
+		}
+		if got := Min(c.args...); got != c.min {
+			t.Errorf("Min(%v) = %v, want %v", c.args, got, c.min)
+		}
+		if got, idx := MinIndex(c.args...); got != c.min || idx != c.minIdx {
+			t.Errorf("MinIndex(%v) = %v, %d, want %v, %d", c.args, got, idx, c.min, c.minIdx)
+		}
+		if got := ArgMin(c.args...); got != c.minIdx {
+			t.Errorf("ArgMin(%v) = %d, want %d", c.args, got, c.minIdx)
+		}
+		if got := Median(c.args...); got != c.median {
+			t.Errorf("Median(%v) = %v, want %v", c.args, got, c.median)
+		}
+		if got := Mean(c.args...); got != c.mean {
+			t.Errorf("Mean(%v) = %v, want %v", c.args, got, c.mean)
+		}
+		if got := Mode(c.args...); !reflect.DeepEqual(got, c.mode) {
+			t.Errorf("Mode(%v) = %v, want %v", c.args, got, c.mode)
+		}
+	}
+}
+
+func TestInt(t *testing.T) {
+	checkNumbers(t, []numberCase[int]{
+		// Nothing to work on
+		{args: nil, product: 1, maxIdx: -1, minIdx: -1},
+		{args: []int{7}, sum: 7, product: 7, max: 7, min: 7, median: 7, mean: 7, mode: []int{7}},
+		// The largest comes first
+		{args: []int{5, 1}, sum: 6, product: 5, max: 5, maxIdx: 0, min: 1, minIdx: 1, median: 3, mean: 3, mode: []int{1, 5}},
+		// Odd length, with the first of repeated extremes
+		{args: []int{3, 9, 1, 9, 1}, sum: 23, product: 243, max: 9, maxIdx: 1, min: 1, minIdx: 2, median: 3, mean: 4.6, mode: []int{1, 9}},
+		// Ties counted before a new maximum are dropped
+		{args: []int{4, 4, 2, 2, 6, 6, 6}, sum: 30, product: 4 * 4 * 2 * 2 * 6 * 6 * 6, max: 6, maxIdx: 4, min: 2, minIdx: 2, median: 4, mean: 30.0 / 7, mode: []int{6}},
+		{args: []int{-2, 0, 2}, sum: 0, product: 0, max: 2, maxIdx: 2, min: -2, minIdx: 0, median: 0, mean: 0, mode: []int{-2, 0, 2}},
+	})
+}
+
+func TestInt64(t *testing.T) {
+	checkNumbers(t, []numberCase[int64]{
+		{args: nil, product: 1, maxIdx: -1, minIdx: -1},
+		{args: []int64{5, 1}, sum: 6, product: 5, max: 5, maxIdx: 0, min: 1, minIdx: 1, median: 3, mean: 3, mode: []int64{1, 5}},
+		// Even length rounds the median down like integer division
+		{args: []int64{1 << 40, 2, 3, 1}, sum: 1<<40 + 6, product: 6 << 40, max: 1 << 40, maxIdx: 0, min: 1, minIdx: 3, median: 2, mean: float64(1<<40+6) / 4, mode: []int64{1, 2, 3, 1 << 40}},
+	})
+}
+
+func TestFloat64(t *testing.T) {
+	checkNumbers(t, []numberCase[float64]{
+		{args: nil, product: 1, maxIdx: -1, minIdx: -1},
+		{args: []float64{5, 1}, sum: 6, product: 5, max: 5, maxIdx: 0, min: 1, minIdx: 1, median: 3, mean: 3, mode: []float64{1, 5}},
+		// Even length averages the middle two
+		{args: []float64{0.5, 4, 1.5, 3}, sum: 9, product: 9, max: 4, maxIdx: 1, min: 0.5, minIdx: 0, median: 2.25, mean: 2.25, mode: []float64{0.5, 1.5, 3, 4}},
+		{args: []float64{2.5, -1, 2.5}, sum: 4, product: -6.25, max: 2.5, maxIdx: 0, min: -1, minIdx: 1, median: 2.5, mean: 4.0 / 3, mode: []float64{2.5}},
+	})
+}
+
+func TestAbs(t *testing.T) {
+	for _, c := range []struct{ in, want int }{{-3, 3}, {0, 0}, {4, 4}} {
+		if got := Abs(c.in); got != c.want {
+			t.Errorf("Abs(%d) = %d, want %d", c.in, got, c.want)
+		}
+	}
+	if got := Abs(-1.5); got != 1.5 {
+		t.Errorf("Abs(-1.5) = %v, want 1.5", got)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	for _, c := range []struct{ n, want int }{{0, 0}, {1, 1}, {4, 10}, {100, 5050}} {
+		if got := Sequence(c.n); got != c.want {
+			t.Errorf("Sequence(%d) = %d, want %d", c.n, got, c.want)
+		}
+	}
+	if got := Sequence(int64(1 << 20)); got != (1<<20)*(1<<20+1)/2 {
+		t.Errorf("Sequence(1<<20) = %d", got)
+	}
+	if got := Sequence(3.0); got != 6 {
+		t.Errorf("Sequence(3.0) = %v, want 6", got)
+	}
+}
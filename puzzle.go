@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"aoc/client"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// savePuzzle saves the description, example input and example answers of a day into dir
+func savePuzzle(client *client.Client, year, day, dir string) error {
+	doc, err := client.Document(fmt.Sprintf("%s/%s/day/%s", client.URL, year, day))
+	if err != nil {
+		return err
+	}
+
+	if err := saveDescription(doc, client.URL, dir+"/README.md"); err != nil {
+		return err
+	}
+	if err := saveExample(doc, dir); err != nil {
+		return err
+	}
+	return saveSolved(doc, dir)
+}
+
+// saveSolved records the answers shown for solved parts, so days solved elsewhere can be verified
+func saveSolved(doc *goquery.Document, dir string) error {
+	history, err := client.LoadHistory(dir)
+	if err != nil {
+		return err
+	}
+
+	var answers []string
+	doc.Find("main p:contains('Your puzzle answer was')").Each(func(i int, s *goquery.Selection) {
+		answers = append(answers, strings.TrimSpace(s.Find("code").First().Text()))
+	})
+
+	for i, answer := range answers {
+		level := i + 1
+		if _, ok := history.Correct(level); ok || answer == "" {
+			continue
+		}
+		if err := history.Record(dir, level, answer, client.Result{Verdict: client.Correct}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveExample writes example.txt and example_answers.txt, one answer per part, from the puzzle
+func saveExample(doc *goquery.Document, dir string) error {
+	articles := doc.Find("article.day-desc")
+	if articles.Length() == 0 {
+		return nil
+	}
+
+	// Don't replace an example that was already saved or made by hand
+	exampleName := dir + "/example.txt"
+	if _, err := os.Stat(exampleName); err != nil {
+		example, ok := findExample(articles.First())
+		if ok {
+			err := writeFile(exampleName, false, func(w io.Writer) error {
+				_, err := io.WriteString(w, example)
+				return err
+			})
+			if err != nil && !errors.Is(err, os.ErrExist) {
+				return err
+			}
+		}
+	}
+
+	// Write out the answers each time since part two may have unlocked
+	answers := make([]string, 0, articles.Length())
+	articles.Each(func(i int, s *goquery.Selection) {
+		answers = append(answers, findExampleAnswer(s))
+	})
+	return writeFile(dir+"/example_answers.txt", true, func(w io.Writer) error {
+		_, err := io.WriteString(w, strings.Join(answers, "\n")+"\n")
+		return err
+	})
+}
+
+// findExample returns the first code block after a paragraph mentioning an example
+func findExample(article *goquery.Selection) (example string, ok bool) {
+	article.Find("p").EachWithBreak(func(i int, p *goquery.Selection) bool {
+		if !strings.Contains(strings.ToLower(p.Text()), "for example") {
+			return true
+		}
+		code := p.NextAllFiltered("pre").First().Find("code")
+		if code.Length() == 0 {
+			return true
+		}
+		example, ok = code.Text(), true
+		return false
+	})
+
+	// Fall back to the first code block at all
+	if !ok {
+		if code := article.Find("pre code").First(); code.Length() != 0 {
+			example, ok = code.Text(), true
+		}
+	}
+	return example, ok
+}
+
+// findExampleAnswer returns the last emphasized code in a part, which is the answer to its example
+func findExampleAnswer(article *goquery.Selection) string {
+	return strings.TrimSpace(article.Find("code em, em code").Last().Text())
+}
@@ -0,0 +1,93 @@
+// Package registry collects the solution for every day so one binary can run them all.
+//
+// Each day registers itself from an init function, so importing a day's package
+// is enough to make it available.
+package registry
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// maxLine is the longest input line the scanner will read
+const maxLine = 1024 * 1024
+
+// Solution is the parser and both parts of a day with the types erased
+type Solution struct {
+	Year  int
+	Day   int
+	Parse func(scanner *bufio.Scanner) interface{}
+	Parts [2]func(input interface{}) interface{}
+}
+
+type key struct {
+	year, day int
+}
+
+var solutions = make(map[key]*Solution)
+
+// Register adds the solution of a day, which must only be registered once
+func Register[T, R1, R2 any](year, day int, parse func(scanner *bufio.Scanner) T, part1 func(T) R1, part2 func(T) R2) {
+	k := key{year, day}
+	if _, exists := solutions[k]; exists {
+		panic(fmt.Sprintf("registry: %d/%d registered twice", year, day))
+	}
+
+	solutions[k] = &Solution{
+		Year: year,
+		Day:  day,
+		Parse: func(scanner *bufio.Scanner) interface{} {
+			return parse(scanner)
+		},
+		Parts: [2]func(input interface{}) interface{}{
+			func(input interface{}) interface{} { return part1(input.(T)) },
+			func(input interface{}) interface{} { return part2(input.(T)) },
+		},
+	}
+}
+
+// Get returns the solution for a day
+func Get(year, day int) (*Solution, bool) {
+	s, ok := solutions[key{year, day}]
+	return s, ok
+}
+
+// Days returns the solutions registered for a year in order
+func Days(year int) (days []*Solution) {
+	for k, s := range solutions {
+		if k.year == year {
+			days = append(days, s)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Day < days[j].Day
+	})
+	return days
+}
+
+// Years returns every year with a solution in order
+func Years() (years []int) {
+	seen := make(map[int]bool)
+	for k := range solutions {
+		if !seen[k.year] {
+			seen[k.year] = true
+			years = append(years, k.year)
+		}
+	}
+	sort.Ints(years)
+	return years
+}
+
+// Read parses an input for the solution
+func (s *Solution) Read(r io.Reader) interface{} {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+	return s.Parse(scanner)
+}
+
+// Dir is where the files for the day are kept, relative to the root of the repository
+func (s *Solution) Dir() string {
+	return fmt.Sprintf("%d/%d", s.Year, s.Day)
+}
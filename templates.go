@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	templateDir = "templates"
+	// goTemplate is the skeleton of every Go solution, which the parsers fill in
+	goTemplate = "go"
+	// autoTemplate picks the template from the shape of the input
+	autoTemplate = "auto"
+)
+
+// templateData fills in the placeholders of the templates
+type templateData struct {
+	Year  string
+	Day   string
+	Title string
+	// Summary describes the shape of the input
+	Summary string
+	// Parser, Type and Var are the input function getInput calls, what it returns and what to call that
+	Parser string
+	Type   string
+	Var    string
+}
+
+// parser is a function of the input package that parses one shape of input for getInput
+type parser struct {
+	Func string
+	Type string
+	Var  string
+}
+
+// parsers are the shapes of input the Go template can parse, by the name of the template
+var parsers = map[string]parser{
+	"lines":     {"Lines", "[]string", "lines"},
+	"ints":      {"Ints", "[]int", "numbers"},
+	"commaints": {"CommaInts", "[]int", "numbers"},
+	"digits":    {"Digits", "[][]int", "grid"},
+	"blocks":    {"Blocks", "[][]string", "blocks"},
+	"commands":  {"Commands", "[]input.Command", "commands"},
+	"bits":      {"Bits", "[][]bool", "rows"},
+	"segments":  {"Segments", "[]input.Segment", "segments"},
+}
+
+// titleRegex finds the title in the first heading of a day's README.md
+var titleRegex = regexp.MustCompile(`(?m)^## --- Day \d+: (.+) ---$`)
+
+// templateNames lists the solution templates: the Go template once for each parser,
+// and the other files in templateDir, which are named NAME.EXT.tmpl
+func templateNames() []string {
+	names := make([]string, 0, len(parsers))
+	for name := range parsers {
+		names = append(names, name)
+	}
+	matches, _ := filepath.Glob(filepath.Join(templateDir, "*.tmpl"))
+	for _, match := range matches {
+		if filepath.Base(match) == filepath.Base(testTemplate) {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(match), ".tmpl")
+		if name = strings.TrimSuffix(name, filepath.Ext(name)); name != goTemplate {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findTemplate returns the file of the named template, the file it becomes in a day, such as main.go,
+// and the parser to fill in if it's the Go template
+func findTemplate(name string) (src, dst string, p parser, err error) {
+	file := name
+	if _, ok := parsers[name]; ok {
+		file = goTemplate
+	} else if name == goTemplate {
+		// The Go template can't be used without a parser
+		file = ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(templateDir, file+".*.tmpl"))
+	if file == "" || len(matches) != 1 {
+		return "", "", parser{}, fmt.Errorf("unknown template %q, use one of %s or %s", name, strings.Join(templateNames(), ", "), autoTemplate)
+	}
+	ext := filepath.Ext(strings.TrimSuffix(matches[0], ".tmpl"))
+	return matches[0], "main" + ext, parsers[name], nil
+}
+
+// puzzleTitle returns the title of the puzzle saved in a day's README.md, if there is one
+func puzzleTitle(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		return ""
+	}
+	if match := titleRegex.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// copyTemplate fills in the template for the day, with p as the parser of a Go solution, and writes it to dst.
+// An existing file is only replaced when forced.
+func copyTemplate(dst, src, year, day string, p parser, force bool) error {
+	tmpl, err := template.ParseFiles(src)
+	if err != nil {
+		return err
+	}
+
+	// Fill it in before writing anything, so a broken template doesn't leave an empty file
+	dir := filepath.Dir(dst)
+	data := templateData{Year: year, Day: day, Title: puzzleTitle(dir), Parser: p.Func, Type: p.Type, Var: p.Var}
+	if input, err := os.ReadFile(filepath.Join(dir, "input.txt")); err == nil {
+		data.Summary = analyzeInput(input).summary
+	}
+	b := &bytes.Buffer{}
+	if err := tmpl.Execute(b, data); err != nil {
+		return err
+	}
+
+	return writeFile(dst, force, func(w io.Writer) error {
+		_, err := b.WriteTo(w)
+		return err
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"aoc/client"
+)
+
+const (
+	// How long to keep trying after the unlock in case the input isn't served yet
+	unlockRetryWindow = time.Minute
+	unlockRetryDelay  = 2 * time.Second
+)
+
+// countdown sleeps until t while showing how long is left
+func countdown(t time.Time, label string) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		left := time.Until(t).Round(time.Second)
+		if left <= 0 {
+			break
+		}
+		// Overwrite the same line with the countdown
+		fmt.Fprintf(os.Stderr, "\r%s in %v   ", label, left)
+		<-ticker.C
+	}
+	// Clear the countdown line
+	fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", len(label)+20))
+}
+
+func waitAndInitialize(c *client.Client, fs *flag.FlagSet, opts *options) {
+	if opts.day == 0 {
+		opts.day = client.NextUnlock(opts.year, time.Now())
+		if opts.day == 0 {
+			log.Fatalln("every day of", opts.year, "is already unlocked")
+		}
+	}
+	opts.requireDay(fs)
+
+	unlock := client.UnlockTime(opts.year, opts.day)
+	log.Printf("Waiting for %s to unlock at %s\n", opts.dayDir(), unlock.Local().Format(time.Kitchen))
+	countdown(unlock, "Unlocking")
+	log.Println("Unlocked!")
+
+	// The input can 404 for a few seconds after the unlock, so keep trying
+	deadline := time.Now().Add(unlockRetryWindow)
+	for {
+		skipped, err := initializeDay(c, opts.yearStr(), opts.dayStr(), opts.force, opts.template)
+		if skipped {
+			log.Println("Skipping", opts.dayDir(), "since it already exists")
+		}
+		if err == nil {
+			break
+		}
+
+		var statusErr *statusError
+		if !errors.As(err, &statusErr) || statusErr.code != http.StatusNotFound || time.Now().After(deadline) {
+			log.Fatalln(err)
+		}
+		log.Println(err, "- retrying")
+		time.Sleep(unlockRetryDelay)
+	}
+
+	// Let the runner know about the new day
+	if err := updateImports(); err != nil {
+		log.Fatalln(err)
+	}
+}